@@ -0,0 +1,367 @@
+// Package audit records every call a server handles, with its caller, its
+// requests and responses and its status, as JSON lines appended to a file
+// that is rotated by size. Command replay sends the recorded calls again.
+//
+// A record looks like this, on one line:
+//
+//	{"time": "2024-05-01T10:00:00Z", "method": "/greet.v1.GreetService/Greet",
+//	 "caller": "ip:10.0.0.7 grpc-go/1.79.3", "metadata": {"pace-interval": ["1s"]},
+//	 "requests": [{"greeting": {"firstName": "Ann"}}],
+//	 "responses": [{"result": "Hello Ann"}], "code": "OK", "duration": "120µs"}
+//
+// Messages are in the protobuf JSON mapping; the raw messages of proxied calls
+// are decoded with the types registered for their method. Fields named in
+// config.Audit.Redact are redacted, and so are their twins in the legacy or
+// v1 messages; credentials are left out of the metadata and API keys are
+// hashed in the caller, see auth.Caller. A record that cannot be written is
+// logged; the call is not failed for it.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/legacy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for the zero values of config.Audit.
+const (
+	DefaultMaxSize     = 100 << 20
+	DefaultMaxBackups  = 10
+	DefaultMaxMessages = 1000
+)
+
+// Redacted replaces redacted strings.
+const Redacted = "REDACTED"
+
+// Record is one call.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	Caller string    `json:"caller"`
+	// Metadata is the request metadata without credentials, transport
+	// headers and the user agent, which is part of Caller.
+	Metadata  metadata.MD       `json:"metadata,omitempty"`
+	Requests  []json.RawMessage `json:"requests"`
+	Responses []json.RawMessage `json:"responses"`
+	// Truncated is set when messages past config.Audit.MaxMessages were not
+	// recorded.
+	Truncated bool            `json:"truncated,omitempty"`
+	Code      string          `json:"code"`
+	Message   string          `json:"message,omitempty"`
+	Duration  config.Duration `json:"duration"`
+}
+
+// omitted are the metadata keys left out of records.
+var omitted = map[string]bool{
+	"authorization":   true,
+	auth.APIKeyHeader: true,
+	"cookie":          true,
+	"user-agent":      true,
+	"content-type":    true,
+}
+
+// Logger writes the records. A Logger without a path passes calls through.
+type Logger struct {
+	out         *file
+	maxMessages int
+	redact      map[protoreflect.FullName]bool
+}
+
+// New opens the audit log configured by cfg.
+func New(cfg config.Audit) (*Logger, error) {
+	l := &Logger{maxMessages: cfg.MaxMessages, redact: map[protoreflect.FullName]bool{}}
+	if l.maxMessages <= 0 {
+		l.maxMessages = DefaultMaxMessages
+	}
+	// A misspelt name would leave its field in the log, so every name has to
+	// be a field of a registered message.
+	for _, name := range cfg.Redact {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("audit: redact %q: no such field", name)
+		}
+		fd, ok := d.(protoreflect.FieldDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("audit: redact %q: not a field", name)
+		}
+		l.redact[fd.FullName()] = true
+		// Legacy calls are recorded with the legacy messages, which must
+		// not leak what the v1 ones hide, nor the other way round.
+		for _, twin := range twinFields(fd) {
+			l.redact[twin] = true
+		}
+	}
+	if cfg.Path == "" {
+		return l, nil
+	}
+
+	maxSize, maxBackups := cfg.MaxSize, cfg.MaxBackups
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultMaxBackups
+	}
+	out, err := openFile(cfg.Path, maxSize, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+	l.out = out
+	return l, nil
+}
+
+// Close closes the log file.
+func (l *Logger) Close() error {
+	if l.out == nil {
+		return nil
+	}
+	return l.out.close()
+}
+
+// twinFields returns the fields of the wire compatible twins of the message
+// of fd, which sit in the same place of the legacy and v1 methods, see
+// legacy.SuccessorMethod.
+func twinFields(fd protoreflect.FieldDescriptor) []protoreflect.FullName {
+	twins := map[protoreflect.FullName][]protoreflect.MessageDescriptor{}
+	var pair func(a, b protoreflect.MessageDescriptor)
+	pair = func(a, b protoreflect.MessageDescriptor) {
+		for _, t := range twins[a.FullName()] {
+			if t.FullName() == b.FullName() {
+				return
+			}
+		}
+		twins[a.FullName()] = append(twins[a.FullName()], b)
+		twins[b.FullName()] = append(twins[b.FullName()], a)
+		fields := a.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			af := fields.Get(i)
+			bf := b.Fields().ByNumber(af.Number())
+			if af.Message() != nil && bf != nil && bf.Message() != nil {
+				pair(af.Message(), bf.Message())
+			}
+		}
+	}
+	for old := range legacy.Successors {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(old))
+		if err != nil {
+			continue
+		}
+		methods := d.(protoreflect.ServiceDescriptor).Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			successor, _ := legacy.SuccessorMethod("/" + old + "/" + string(md.Name()))
+			sd, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(strings.ReplaceAll(successor[1:], "/", ".")))
+			if err != nil {
+				continue
+			}
+			pair(md.Input(), sd.(protoreflect.MethodDescriptor).Input())
+			pair(md.Output(), sd.(protoreflect.MethodDescriptor).Output())
+		}
+	}
+
+	var names []protoreflect.FullName
+	for _, t := range twins[fd.ContainingMessage().FullName()] {
+		if f := t.Fields().ByNumber(fd.Number()); f != nil {
+			names = append(names, f.FullName())
+		}
+	}
+	return names
+}
+
+// call collects the record of a call in progress. Stream messages may be
+// sent and received concurrently.
+type call struct {
+	l     *Logger
+	start time.Time
+
+	mu  sync.Mutex
+	rec Record
+}
+
+func (l *Logger) begin(ctx context.Context, method string) *call {
+	md, _ := metadata.FromIncomingContext(ctx)
+	kept := metadata.MD{}
+	for key, values := range md {
+		if !omitted[key] && !strings.HasPrefix(key, ":") {
+			kept[key] = values
+		}
+	}
+	start := time.Now()
+	return &call{l: l, start: start, rec: Record{
+		Time:      start.UTC(),
+		Method:    method,
+		Caller:    auth.Caller(ctx),
+		Metadata:  kept,
+		Requests:  []json.RawMessage{},
+		Responses: []json.RawMessage{},
+	}}
+}
+
+func (c *call) add(list *[]json.RawMessage, m any, request bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(*list) >= c.l.maxMessages {
+		c.rec.Truncated = true
+		return
+	}
+	msg, err := decode(c.rec.Method, m, request)
+	var data []byte
+	if err == nil {
+		data, err = protojson.Marshal(c.l.redacted(msg))
+	}
+	if err != nil {
+		data, _ = json.Marshal(err.Error())
+	}
+	*list = append(*list, data)
+}
+
+func (c *call) request(m any)  { c.add(&c.rec.Requests, m, true) }
+func (c *call) response(m any) { c.add(&c.rec.Responses, m, false) }
+
+// decode returns m as a message. Proxies pass raw bytes, see package rawcodec,
+// which are decoded as the input or output type of the method.
+func decode(method string, m any, request bool) (proto.Message, error) {
+	switch m := m.(type) {
+	case proto.Message:
+		return m, nil
+	case *[]byte:
+		name := strings.ReplaceAll(strings.TrimPrefix(method, "/"), "/", ".")
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, err
+		}
+		md, ok := d.(protoreflect.MethodDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a method", name)
+		}
+		desc := md.Output()
+		if request {
+			desc = md.Input()
+		}
+		typ, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+		if err != nil {
+			return nil, err
+		}
+		msg := typ.New().Interface()
+		return msg, proto.Unmarshal(*m, msg)
+	}
+	return nil, fmt.Errorf("cannot record %T", m)
+}
+
+func (c *call) end(err error) {
+	st := status.Convert(err)
+	c.mu.Lock()
+	c.rec.Code = st.Code().String()
+	c.rec.Message = st.Message()
+	c.rec.Duration = config.Duration(time.Since(c.start))
+	data, merr := json.Marshal(c.rec)
+	c.mu.Unlock()
+
+	if merr == nil {
+		merr = c.l.out.write(append(data, '\n'))
+	}
+	if merr != nil {
+		log.Printf("audit: recording %s: %v", c.rec.Method, merr)
+	}
+}
+
+// redacted returns m, or a copy of it without the values of redacted fields.
+func (l *Logger) redacted(m proto.Message) proto.Message {
+	if len(l.redact) == 0 {
+		return m
+	}
+	m = proto.Clone(m)
+	l.redactMessage(m.ProtoReflect())
+	return m
+}
+
+func (l *Logger) redactMessage(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case l.redact[fd.FullName()]:
+			if fd.Kind() != protoreflect.StringKind || fd.IsMap() {
+				m.Clear(fd)
+			} else if fd.IsList() {
+				for i := 0; i < v.List().Len(); i++ {
+					v.List().Set(i, protoreflect.ValueOfString(Redacted))
+				}
+			} else {
+				m.Set(fd, protoreflect.ValueOfString(Redacted))
+			}
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len(); i++ {
+				l.redactMessage(v.List().Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				l.redactMessage(v.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			l.redactMessage(v.Message())
+		}
+		return true
+	})
+}
+
+// UnaryInterceptor records unary calls.
+func (l *Logger) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if l.out == nil {
+		return handler(ctx, req)
+	}
+	c := l.begin(ctx, info.FullMethod)
+	c.request(req)
+	res, err := handler(ctx, req)
+	if err == nil {
+		c.response(res)
+	}
+	c.end(err)
+	return res, err
+}
+
+// StreamInterceptor records streams, every message in the order it was
+// received or sent.
+func (l *Logger) StreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if l.out == nil {
+		return handler(srv, ss)
+	}
+	c := l.begin(ss.Context(), info.FullMethod)
+	err := handler(srv, &recordedStream{ServerStream: ss, call: c})
+	c.end(err)
+	return err
+}
+
+type recordedStream struct {
+	grpc.ServerStream
+	call *call
+}
+
+func (s *recordedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.call.request(m)
+	return nil
+}
+
+func (s *recordedStream) SendMsg(m any) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.call.response(m)
+	return nil
+}
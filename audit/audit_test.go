@@ -0,0 +1,287 @@
+package audit_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/ferza17/grpc-course/audit"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/proxy"
+	"github.com/ferza17/grpc-course/rawcodec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// start serves GreetService recording calls as cfg says, with cfg.Path in a
+// temporary directory.
+func start(t *testing.T, cfg config.Audit) (greetv1.GreetServiceClient, string) {
+	cc, path := startConn(t, cfg)
+	return greetv1.NewGreetServiceClient(cc), path
+}
+
+// startConn is start returning the connection, for the legacy names.
+func startConn(t *testing.T, cfg config.Audit) (*grpc.ClientConn, string) {
+	cfg.Path = filepath.Join(t.TempDir(), "audit.log")
+	l, err := audit.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	a, err := auth.New(config.Auth{APIKeys: []string{"secret-key"}})
+	if err != nil {
+		t.Fatalf("auth.New: %v", err)
+	}
+	h := harness.Greet(t,
+		grpc.ChainUnaryInterceptor(a.UnaryInterceptor, l.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(a.StreamInterceptor, l.StreamInterceptor),
+	)
+	return h.Conn, cfg.Path
+}
+
+func readRecords(t *testing.T, path string) []audit.Record {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var records []audit.Record
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		var rec audit.Record
+		if err := json.Unmarshal(lines.Bytes(), &rec); err != nil {
+			t.Fatalf("record %q: %v", lines.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func messages(raw []json.RawMessage) string {
+	s := make([]string, len(raw))
+	for i, m := range raw {
+		s[i] = strings.ReplaceAll(string(m), " ", "")
+	}
+	return strings.Join(s, ",")
+}
+
+func greeting(first, last string) *greetv1.Greeting {
+	return &greetv1.Greeting{FirstName: first, LastName: last}
+}
+
+func TestRecords(t *testing.T) {
+	c, path := start(t, config.Audit{})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		auth.APIKeyHeader, "secret-key",
+		"authorization", "Bearer token",
+		"x-request-id", "r1",
+	)
+	if _, err := c.Greet(ctx, &greetv1.GreetRequest{Greeting: greeting("Ann", "")}); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	stream, err := c.GreetEveryone(context.Background())
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	for _, name := range []string{"Ann", "Bo"} {
+		if err := stream.Send(&greetv1.GreetEveryoneRequest{Greeting: greeting(name, "")}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+	// The status is sent once the interceptors have returned, so the
+	// records are written by now.
+	records := readRecords(t, path)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	greet := records[0]
+	if greet.Method != "/greet.v1.GreetService/Greet" || greet.Code != "OK" {
+		t.Errorf("record = %s %s, want /greet.v1.GreetService/Greet OK", greet.Method, greet.Code)
+	}
+	if !strings.HasPrefix(greet.Caller, "key:sha256:") || strings.Contains(greet.Caller, "secret-key") {
+		t.Errorf("caller = %q, want a hashed API key", greet.Caller)
+	}
+	if got := greet.Metadata.Get("x-request-id"); len(got) != 1 || got[0] != "r1" {
+		t.Errorf("x-request-id = %v, want r1", got)
+	}
+	for _, key := range []string{auth.APIKeyHeader, "authorization", "user-agent"} {
+		if got := greet.Metadata.Get(key); len(got) > 0 {
+			t.Errorf("%s recorded as %v", key, got)
+		}
+	}
+	if got, want := messages(greet.Requests), `{"greeting":{"firstName":"Ann"}}`; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+	if got, want := messages(greet.Responses), `{"result":"HelloAnn"}`; got != want {
+		t.Errorf("responses = %s, want %s", got, want)
+	}
+
+	bidi := records[1]
+	if got, want := messages(bidi.Requests), `{"greeting":{"firstName":"Ann"}},{"greeting":{"firstName":"Bo"}}`; got != want {
+		t.Errorf("stream requests = %s, want %s", got, want)
+	}
+	if got, want := messages(bidi.Responses), `{"result":"HelloAnn!"},{"result":"HelloBo!"}`; got != want {
+		t.Errorf("stream responses = %s, want %s", got, want)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	c, path := start(t, config.Audit{Redact: []string{"greet.v1.Greeting.last_name"}})
+
+	if _, err := c.Greet(context.Background(), &greetv1.GreetRequest{Greeting: greeting("Ann", "Lee")}); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	records := readRecords(t, path)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	if got, want := messages(records[0].Requests), `{"greeting":{"firstName":"Ann","lastName":"REDACTED"}}`; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+}
+
+func TestRedactLegacyTwins(t *testing.T) {
+	for _, tt := range []struct {
+		redact string
+		// legacy calls the legacy name rather than v1.
+		legacy bool
+	}{
+		{"greet.v1.Greeting.last_name", true},
+		{"greet.Greeting.last_name", false},
+	} {
+		cc, path := startConn(t, config.Audit{Redact: []string{tt.redact}})
+		var err error
+		if tt.legacy {
+			_, err = greetpb.NewGreatServiceClient(cc).Greet(context.Background(), &greetpb.GreatRequest{Greeting: &greetpb.Greeting{FirstName: "Ann", LastName: "Lee"}})
+		} else {
+			_, err = greetv1.NewGreetServiceClient(cc).Greet(context.Background(), &greetv1.GreetRequest{Greeting: greeting("Ann", "Lee")})
+		}
+		if err != nil {
+			t.Fatalf("Greet: %v", err)
+		}
+		records := readRecords(t, path)
+		if len(records) != 1 {
+			t.Fatalf("got %d records, want 1", len(records))
+		}
+		if got, want := messages(records[0].Requests), `{"greeting":{"firstName":"Ann","lastName":"REDACTED"}}`; got != want {
+			t.Errorf("redacting %s: %s requests = %s, want %s", tt.redact, records[0].Method, got, want)
+		}
+	}
+}
+
+func TestRedactUnknownField(t *testing.T) {
+	for _, name := range []string{"greet.v1.Greeting.lastname", "greet.v1.Greeting", "nope"} {
+		if _, err := audit.New(config.Audit{Redact: []string{name}}); err == nil {
+			t.Errorf("New accepted redacting %q", name)
+		}
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	c, path := start(t, config.Audit{MaxMessages: 2})
+
+	stream, err := c.GreetManyTimes(context.Background(), &greetv1.GreetManyTimesRequest{Greeting: greeting("Ann", "")})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+	}
+
+	rec := readRecords(t, path)[0]
+	if !rec.Truncated || len(rec.Responses) != 2 {
+		t.Errorf("record has %d responses, truncated %v; want 2, true", len(rec.Responses), rec.Truncated)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	c, path := start(t, config.Audit{MaxSize: 400, MaxBackups: 2})
+
+	for i := 0; i < 10; i++ {
+		if _, err := c.Greet(context.Background(), &greetv1.GreetRequest{Greeting: greeting("Ann", "")}); err != nil {
+			t.Fatalf("Greet: %v", err)
+		}
+	}
+
+	total := 0
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		if info.Size() > 400 {
+			t.Errorf("%s has %d bytes, want at most 400", name, info.Size())
+		}
+		total += len(readRecords(t, name))
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("stat %s.3 = %v, want not exist", path, err)
+	}
+	if total >= 10 || total == 0 {
+		t.Errorf("%d records kept, want some but not all 10", total)
+	}
+}
+
+func TestProxied(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := audit.New(config.Audit{Path: path})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	backend := harness.Greet(t)
+	h := harness.New(t, func(*grpc.Server) {},
+		grpc.UnknownServiceHandler(func(_ any, ss grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(ss)
+			return proxy.Forward(ss, backend.Conn, method)
+		}),
+		grpc.ForceServerCodec(rawcodec.Codec{}),
+		grpc.StreamInterceptor(l.StreamInterceptor),
+	)
+
+	c := greetv1.NewGreetServiceClient(h.Conn)
+	if _, err := c.Greet(context.Background(), &greetv1.GreetRequest{Greeting: greeting("Ann", "")}); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	rec := readRecords(t, path)[0]
+	if got, want := messages(rec.Requests), `{"greeting":{"firstName":"Ann"}}`; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+	if got, want := messages(rec.Responses), `{"result":"HelloAnn"}`; got != want {
+		t.Errorf("responses = %s, want %s", got, want)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	l, err := audit.New(config.Audit{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	c := greetv1.NewGreetServiceClient(harness.Greet(t, grpc.UnaryInterceptor(l.UnaryInterceptor)).Conn)
+	if _, err := c.Greet(context.Background(), &greetv1.GreetRequest{Greeting: greeting("Ann", "")}); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+}
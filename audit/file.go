@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// file is an append-only log file rotated by size.
+type file struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+func openFile(path string, maxSize int64, maxBackups int) (*file, error) {
+	w := &file{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *file) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f, w.size = f, info.Size()
+	return nil
+}
+
+// write appends one record. A record is never split over two files.
+func (w *file) write(record []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.size+int64(len(record)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.f.Write(record)
+	w.size += int64(n)
+	return err
+}
+
+// rotate shifts path.N-1 to path.N down to path to path.1, dropping the
+// oldest, and starts a new file.
+func (w *file) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	os.Remove(backup(w.path, w.maxBackups))
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		os.Rename(backup(w.path, i), backup(w.path, i+1))
+	}
+	if err := os.Rename(w.path, backup(w.path, 1)); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func backup(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+func (w *file) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}
@@ -0,0 +1,262 @@
+// Package auth establishes who a caller is. An Authenticator checks the API
+// key or the bearer JWT of every call against config.Auth and refuses calls
+// whose credentials fail with Unauthenticated; calls without credentials are
+// anonymous and named by their address. Identity then tells callers apart for
+// the rate limiter, the idempotency store and the audit log, so the
+// Authenticator has to run before those interceptors.
+//
+// The HTTP handlers and proxy_server call on behalf of someone else and pass
+// the original client address in x-forwarded-for. The header is believed from
+// loopback, Unix socket and in-process peers, from the address of the server
+// itself, see TrustListener, and from config.Auth.TrustedProxies; from anyone
+// else it would let callers pick their own identity, so it is ignored.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ferza17/grpc-course/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// APIKeyHeader is the metadata key carrying an API key.
+	APIKeyHeader = "x-api-key"
+	// ForwardedForHeader is the metadata key carrying the client address of
+	// calls relayed by a trusted peer.
+	ForwardedForHeader = "x-forwarded-for"
+)
+
+// caller is what the Authenticator learnt about a call.
+type caller struct {
+	// identity is "key:<api key>" or "sub:<subject>", empty for anonymous
+	// callers.
+	identity string
+	addr     string
+	admin    bool
+}
+
+type callerKey struct{}
+
+// Authenticator verifies credentials and resolves client addresses.
+type Authenticator struct {
+	// keys are the SHA-256 sums of the API keys, so looking one up takes
+	// the same time whatever its prefix.
+	keys    map[[sha256.Size]byte]bool
+	secret  []byte
+	admins  map[string]bool
+	trusted []*net.IPNet
+}
+
+// New returns an Authenticator enforcing cfg.
+func New(cfg config.Auth) (*Authenticator, error) {
+	a := &Authenticator{keys: map[[sha256.Size]byte]bool{}, admins: map[string]bool{}}
+	for _, key := range cfg.APIKeys {
+		a.keys[sha256.Sum256([]byte(key))] = true
+	}
+	if cfg.JWTSecret != "" {
+		a.secret = []byte(cfg.JWTSecret)
+	}
+	for _, id := range cfg.Admins {
+		a.admins[id] = true
+	}
+	for _, s := range cfg.TrustedProxies {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxy %q is not an address or CIDR range", s)
+			}
+			s = ip.String() + "/128"
+			if ip.To4() != nil {
+				s = ip.String() + "/32"
+			}
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is not an address or CIDR range", s)
+		}
+		a.trusted = append(a.trusted, n)
+	}
+	return a, nil
+}
+
+// TrustListener trusts peers calling from addr, the address of a listener of
+// this server. Its HTTP handlers dial it with transport.DialListener, from
+// that address when the listener is bound to one that is not loopback. Call
+// it before serving.
+func (a *Authenticator) TrustListener(addr net.Addr) {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok || tcp.IP == nil || tcp.IP.IsUnspecified() || tcp.IP.IsLoopback() {
+		return
+	}
+	bits := 8 * net.IPv6len
+	if tcp.IP.To4() != nil {
+		bits = 8 * net.IPv4len
+	}
+	a.trusted = append(a.trusted, &net.IPNet{IP: tcp.IP, Mask: net.CIDRMask(bits, bits)})
+}
+
+// authenticate returns ctx carrying the caller of the call.
+func (a *Authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c := caller{addr: a.clientAddr(ctx, md)}
+
+	if keys := md.Get(APIKeyHeader); len(keys) > 0 && keys[0] != "" && len(a.keys) > 0 {
+		if !a.keys[sha256.Sum256([]byte(keys[0]))] {
+			return nil, status.Error(codes.Unauthenticated, "unknown API key")
+		}
+		c.identity = "key:" + keys[0]
+	} else if auth := md.Get("authorization"); len(auth) > 0 && a.secret != nil {
+		sub, err := a.subject(auth[0])
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "bearer token: %v", err)
+		}
+		c.identity = "sub:" + sub
+	}
+	c.admin = c.identity != "" && a.admins[c.identity]
+	return context.WithValue(ctx, callerKey{}, c), nil
+}
+
+// clientAddr is the host of the peer, or the one it forwarded for if it is
+// trusted.
+func (a *Authenticator) clientAddr(ctx context.Context, md metadata.MD) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := host(p.Addr.String())
+	fwd := md.Get(ForwardedForHeader)
+	if len(fwd) == 0 || !a.trusts(p.Addr) {
+		return addr
+	}
+	// The nearest hop is listed last.
+	hops := strings.Split(fwd[len(fwd)-1], ",")
+	if client := strings.TrimSpace(hops[len(hops)-1]); client != "" {
+		return client
+	}
+	return addr
+}
+
+func (a *Authenticator) trusts(addr net.Addr) bool {
+	switch addr.Network() {
+	case "unix", "inprocess":
+		return true
+	}
+	ip := net.ParseIP(host(addr.String()))
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, n := range a.trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func host(addr string) string {
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		return h
+	}
+	return addr
+}
+
+// subject verifies an HS256 bearer JWT and returns its sub claim.
+func (a *Authenticator) subject(authorization string) (string, error) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	parts := strings.Split(token, ".")
+	if !ok || len(parts) != 3 {
+		return "", errors.New("not a bearer JWT")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", err
+	}
+	if header.Alg != "HS256" {
+		return "", fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed signature")
+	}
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return "", errors.New("invalid signature")
+	}
+
+	var claims struct {
+		Subject   string `json:"sub"`
+		Expires   int64  `json:"exp"`
+		NotBefore int64  `json:"nbf"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", err
+	}
+	now := time.Now().Unix()
+	switch {
+	case claims.Expires != 0 && now >= claims.Expires:
+		return "", errors.New("expired")
+	case claims.NotBefore != 0 && now < claims.NotBefore:
+		return "", errors.New("not valid yet")
+	case claims.Subject == "":
+		return "", errors.New("no subject")
+	}
+	return claims.Subject, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("malformed JWT")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.New("malformed JWT")
+	}
+	return nil
+}
+
+// UnaryInterceptor authenticates unary calls.
+func (a *Authenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor authenticates streams.
+func (a *Authenticator) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
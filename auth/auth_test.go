@@ -0,0 +1,235 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const secret = "jwt-secret"
+
+func newAuthenticator(t *testing.T, cfg config.Auth) *auth.Authenticator {
+	t.Helper()
+	a, err := auth.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return a
+}
+
+// call runs a unary call from the peer at addr with the metadata kv through
+// a and returns the context the handler saw.
+func call(a *auth.Authenticator, addr string, kv ...string) (context.Context, error) {
+	tcp, _ := net.ResolveTCPAddr("tcp", addr)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tcp})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(kv...))
+
+	var got context.Context
+	_, err := a.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/greet.v1.GreetService/Greet"},
+		func(ctx context.Context, _ any) (any, error) {
+			got = ctx
+			return nil, nil
+		})
+	return got, err
+}
+
+// token signs claims with key as an HS256 JWT.
+func token(t *testing.T, key string, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(signed))
+	return "Bearer " + signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAPIKey(t *testing.T) {
+	a := newAuthenticator(t, config.Auth{APIKeys: []string{"k1"}})
+
+	ctx, err := call(a, "203.0.113.5:4000", auth.APIKeyHeader, "k1")
+	if err != nil {
+		t.Fatalf("known key: %v", err)
+	}
+	if got := auth.Identity(ctx); got != "key:k1" {
+		t.Errorf("Identity = %q, want key:k1", got)
+	}
+
+	if _, err := call(a, "203.0.113.5:4000", auth.APIKeyHeader, "k2"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("unknown key = %v, want Unauthenticated", err)
+	}
+
+	ctx, err = call(a, "203.0.113.5:4000")
+	if err != nil {
+		t.Fatalf("anonymous: %v", err)
+	}
+	if got := auth.Identity(ctx); got != "ip:203.0.113.5" {
+		t.Errorf("anonymous Identity = %q, want ip:203.0.113.5", got)
+	}
+}
+
+func TestUnverifiedCredentialsAreIgnored(t *testing.T) {
+	a := newAuthenticator(t, config.Auth{})
+
+	ctx, err := call(a, "203.0.113.5:4000",
+		auth.APIKeyHeader, "made-up",
+		"authorization", token(t, "any", map[string]any{"sub": "ann"}),
+	)
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if got := auth.Identity(ctx); got != "ip:203.0.113.5" {
+		t.Errorf("Identity = %q, want ip:203.0.113.5", got)
+	}
+	if _, ok := auth.Verified(ctx); ok {
+		t.Error("Verified = true without any key or secret configured")
+	}
+}
+
+func TestJWT(t *testing.T) {
+	a := newAuthenticator(t, config.Auth{JWTSecret: secret})
+	hour := time.Hour.Seconds()
+	now := float64(time.Now().Unix())
+
+	ctx, err := call(a, "203.0.113.5:4000", "authorization", token(t, secret, map[string]any{"sub": "ann", "exp": now + hour}))
+	if err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+	if got := auth.Identity(ctx); got != "sub:ann" {
+		t.Errorf("Identity = %q, want sub:ann", got)
+	}
+
+	for name, authorization := range map[string]string{
+		"wrong secret": token(t, "other", map[string]any{"sub": "ann"}),
+		"expired":      token(t, secret, map[string]any{"sub": "ann", "exp": now - hour}),
+		"not yet":      token(t, secret, map[string]any{"sub": "ann", "nbf": now + hour}),
+		"no subject":   token(t, secret, map[string]any{}),
+		"unsigned":     "Bearer eyJhbGciOiJub25lIn0.eyJzdWIiOiJhbm4ifQ.",
+		"not a JWT":    "Basic YW5uOnB3",
+	} {
+		if _, err := call(a, "203.0.113.5:4000", "authorization", authorization); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("%s: %v, want Unauthenticated", name, err)
+		}
+	}
+}
+
+func TestForwardedFor(t *testing.T) {
+	a := newAuthenticator(t, config.Auth{TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1"}})
+
+	for _, tt := range []struct {
+		peer, forwarded, want string
+	}{
+		{"127.0.0.1:5000", "198.51.100.7", "198.51.100.7"},
+		{"[::1]:5000", "198.51.100.7", "198.51.100.7"},
+		{"10.1.2.3:5000", "198.51.100.7", "198.51.100.7"},
+		{"192.0.2.1:5000", "203.0.113.9, 198.51.100.7", "198.51.100.7"},
+		{"192.0.2.2:5000", "198.51.100.7", "192.0.2.2"},
+		{"203.0.113.5:5000", "127.0.0.1", "203.0.113.5"},
+	} {
+		ctx, err := call(a, tt.peer, auth.ForwardedForHeader, tt.forwarded)
+		if err != nil {
+			t.Fatalf("call: %v", err)
+		}
+		if got := auth.ClientAddr(ctx); got != tt.want {
+			t.Errorf("peer %s forwarding %q: ClientAddr = %q, want %q", tt.peer, tt.forwarded, got, tt.want)
+		}
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	a := newAuthenticator(t, config.Auth{JWTSecret: secret, Admins: []string{"sub:ops"}})
+
+	for sub, want := range map[string]bool{"ops": true, "ann": false} {
+		ctx, err := call(a, "203.0.113.5:4000", "authorization", token(t, secret, map[string]any{"sub": sub}))
+		if err != nil {
+			t.Fatalf("call: %v", err)
+		}
+		if got := auth.IsAdmin(ctx); got != want {
+			t.Errorf("IsAdmin of %s = %v, want %v", sub, got, want)
+		}
+	}
+}
+
+func TestNewRejectsBadProxies(t *testing.T) {
+	if _, err := auth.New(config.Auth{TrustedProxies: []string{"proxy.local"}}); err == nil {
+		t.Error("New accepted a host name as trusted proxy")
+	}
+}
+
+func TestSetForwardedFor(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/", nil)
+	r.RemoteAddr = "198.51.100.7:61000"
+	md := metadata.Pairs(auth.ForwardedForHeader, "127.0.0.1")
+
+	auth.SetForwardedFor(md, r)
+	if got := md.Get(auth.ForwardedForHeader); len(got) != 1 || got[0] != "198.51.100.7" {
+		t.Errorf("%s = %q, want the remote address only", auth.ForwardedForHeader, got)
+	}
+}
+
+// nonLoopback returns an address of this host that is not loopback.
+func nonLoopback(t *testing.T) string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		t.Skipf("interface addresses: %v", err)
+	}
+	for _, addr := range addrs {
+		if n, ok := addr.(*net.IPNet); ok && n.IP.To4() != nil && !n.IP.IsLoopback() && !n.IP.IsLinkLocalUnicast() {
+			return n.IP.String()
+		}
+	}
+	t.Skip("no address other than loopback")
+	return ""
+}
+
+func TestTrustListener(t *testing.T) {
+	lis, err := transport.Listen(net.JoinHostPort(nonLoopback(t), "0"), config.Transport{})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	a := newAuthenticator(t, config.Auth{})
+	a.TrustListener(lis.Addr())
+
+	var got string
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(a.UnaryInterceptor, func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		got = auth.ClientAddr(ctx)
+		return handler(ctx, req)
+	}))
+	greetservice.Register(s, &greetservice.Server{Out: io.Discard})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	// The HTTP handlers reach the server like this.
+	cc, err := transport.DialListener(lis)
+	if err != nil {
+		t.Fatalf("DialListener: %v", err)
+	}
+	defer cc.Close()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), auth.ForwardedForHeader, "198.51.100.7")
+	if _, err := greetv1.NewGreetServiceClient(cc).Greet(ctx, &greetv1.GreetRequest{}); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	if got != "198.51.100.7" {
+		t.Errorf("ClientAddr = %q, want the forwarded 198.51.100.7", got)
+	}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net/http"
+	"strings"
+)
+
+// Identity names the caller a request is accounted to: "key:<api key>" or
+// "sub:<subject>" when the Authenticator verified one, otherwise
+// "ip:<client address>". Without an Authenticator in front every caller is
+// named by its peer address.
+func Identity(ctx context.Context) string {
+	if id, ok := Verified(ctx); ok {
+		return id
+	}
+	if addr := ClientAddr(ctx); addr != "" {
+		return "ip:" + addr
+	}
+	return "unknown"
+}
+
+// Verified returns the identity of the caller if the Authenticator verified
+// its credentials.
+func Verified(ctx context.Context) (string, bool) {
+	c, ok := ctx.Value(callerKey{}).(caller)
+	return c.identity, ok && c.identity != ""
+}
+
+// IsAdmin reports whether the caller is one of config.Auth.Admins.
+func IsAdmin(ctx context.Context) bool {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c.admin
+}
+
+// ClientAddr is the host of the client, which differs from the peer for
+// calls relayed by a trusted peer, see the package comment.
+func ClientAddr(ctx context.Context) string {
+	if c, ok := ctx.Value(callerKey{}).(caller); ok {
+		return c.addr
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return host(p.Addr.String())
+	}
+	return ""
+}
+
+// Caller is Identity with API keys replaced by a short hash, so they can be
+// told apart without being published, followed by the user agent, which
+// tells the client library and its version. It is meant for logs.
+func Caller(ctx context.Context) string {
+	id := Identity(ctx)
+	if key := strings.TrimPrefix(id, "key:"); key != id {
+		id = "key:" + KeyHash(key)
+	}
+	if ua := metadata.ValueFromIncomingContext(ctx, "user-agent"); len(ua) > 0 {
+		id += " " + ua[0]
+	}
+	return id
+}
+
+// KeyHash is the short hash Caller publishes API keys as.
+func KeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:4])
+}
+
+// SetForwardedFor names the client of an HTTP request, by its remote address,
+// in the metadata of the call made for it, replacing any value the client
+// sent itself.
+func SetForwardedFor(md metadata.MD, r *http.Request) {
+	md.Set(ForwardedForHeader, host(r.RemoteAddr))
+}
@@ -0,0 +1,18 @@
+#!/bin/sh
+# Fails if the protos break wire or generated-code compatibility with the
+# last release tag, or the main branch before the first release, or with the
+# ref given as the first argument, such as "branch=main".
+set -e
+cd "$(dirname "$0")"
+
+against=$1
+if [ -z "$against" ]; then
+  if tag=$(git describe --tags --abbrev=0 2>/dev/null); then
+    against="tag=$tag"
+  else
+    echo "breaking.sh: no release tag, checking against branch=main" >&2
+    against="branch=main"
+  fi
+fi
+
+buf breaking --against ".git#$against"
@@ -0,0 +1,185 @@
+// Package cache keeps the responses of deterministic methods, unary or server
+// streaming, so repeated requests are answered without running the handler.
+// Methods opt in through config.Cache with a TTL each; entries are keyed on
+// the method and the canonical encoding of the request, and evicted least
+// recently used first when the cache is full.
+//
+// Clients can bypass the cache per call with a cache-control header:
+// "no-cache" skips the lookup but stores the fresh response, "no-store"
+// skips both. Responses carry x-cache: hit or miss. Cached streams are
+// replayed at once, without the pacing of the handler.
+//
+// Counters are published in the expvar "response_cache":
+//
+//	"response_cache": {
+//	  "hits": {"/calculator.v1.CalculatorService/Sum": 12},
+//	  "misses": {"/calculator.v1.CalculatorService/Sum": 3},
+//	  "evictions": 0, "entries": 3, "bytes": 291
+//	}
+package cache
+
+import (
+	"container/list"
+	"expvar"
+	"github.com/ferza17/grpc-course/config"
+	"google.golang.org/protobuf/proto"
+	"sync"
+	"time"
+)
+
+// Defaults for the zero values of config.Cache.
+const (
+	DefaultMaxEntries = 10000
+	DefaultMaxBytes   = 64 << 20
+)
+
+var (
+	hits       = new(expvar.Map)
+	misses     = new(expvar.Map)
+	evictions  = new(expvar.Int)
+	entryCount = new(expvar.Int)
+	byteCount  = new(expvar.Int)
+)
+
+func init() {
+	m := expvar.NewMap("response_cache")
+	m.Set("hits", hits)
+	m.Set("misses", misses)
+	m.Set("evictions", evictions)
+	m.Set("entries", entryCount)
+	m.Set("bytes", byteCount)
+}
+
+// Stats are the counters of one Cache.
+type Stats struct {
+	Hits, Misses, Evictions int64
+	Entries, Bytes          int
+}
+
+type entry struct {
+	key       string
+	method    string
+	responses []proto.Message
+	size      int
+	expires   time.Time
+}
+
+// Cache is an LRU cache of responses. Its settings can be replaced with
+// Update while the server runs.
+type Cache struct {
+	mu      sync.Mutex
+	cfg     config.Cache
+	lru     *list.List // of *entry, most recently used first
+	entries map[string]*list.Element
+	stats   Stats
+}
+
+// New returns a Cache configured by cfg.
+func New(cfg config.Cache) *Cache {
+	return &Cache{cfg: withDefaults(cfg), lru: list.New(), entries: map[string]*list.Element{}}
+}
+
+func withDefaults(cfg config.Cache) config.Cache {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultMaxEntries
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultMaxBytes
+	}
+	return cfg
+}
+
+// Update replaces the settings. Responses of methods no longer cached are
+// dropped, the others keep their expiry.
+func (c *Cache) Update(cfg config.Cache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cfg = withDefaults(cfg)
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*entry); c.cfg.Methods[e.method] <= 0 {
+			c.remove(el)
+		}
+		el = next
+	}
+	c.shrink()
+}
+
+// Stats returns the counters of c.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// ttl returns how long responses of method are kept, zero if not at all.
+func (c *Cache) ttl(method string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Duration(c.cfg.Methods[method])
+}
+
+// get returns the responses stored under key and counts a hit or a miss.
+func (c *Cache) get(method, key string) ([]proto.Message, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if ok && time.Now().After(el.Value.(*entry).expires) {
+		c.remove(el)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		misses.Add(method, 1)
+		return nil, false
+	}
+	c.stats.Hits++
+	hits.Add(method, 1)
+	c.lru.MoveToFront(el)
+	return el.Value.(*entry).responses, true
+}
+
+// put stores the responses of a call. They must not be modified afterwards.
+func (c *Cache) put(method, key string, responses []proto.Message, ttl time.Duration) {
+	size := len(key)
+	for _, res := range responses {
+		size += proto.Size(res)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if size > c.cfg.MaxBytes {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	e := &entry{key: key, method: method, responses: responses, size: size, expires: time.Now().Add(ttl)}
+	c.entries[key] = c.lru.PushFront(e)
+	c.stats.Entries++
+	c.stats.Bytes += size
+	entryCount.Add(1)
+	byteCount.Add(int64(size))
+	c.shrink()
+}
+
+// shrink evicts entries until the cache is within its bounds.
+func (c *Cache) shrink() {
+	for c.stats.Entries > c.cfg.MaxEntries || c.stats.Bytes > c.cfg.MaxBytes {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+		evictions.Add(1)
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*entry)
+	delete(c.entries, e.key)
+	c.stats.Entries--
+	c.stats.Bytes -= e.size
+	entryCount.Add(-1)
+	byteCount.Add(int64(-e.size))
+}
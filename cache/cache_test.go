@@ -0,0 +1,224 @@
+package cache_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/cache"
+	"github.com/ferza17/grpc-course/calculator/calcfake"
+	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/fake"
+	"github.com/ferza17/grpc-course/harness"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	sumData      = "/calculator.SumService/SumData"
+	sumManyTimes = "/calculator.SumService/SumManyTimes"
+)
+
+// start serves a calcfake behind a cache configured by cfg. SumData answers
+// 1, 2, 3... and SumManyTimes streams 2, 3.
+func start(t *testing.T, cfg config.Cache) (*calcfake.Server, calculatorpb.SumServiceClient, *cache.Cache) {
+	f := calcfake.New()
+	for i := int32(1); i <= 10; i++ {
+		f.SumData.Enqueue(fake.Send(&calculatorpb.SumResponse{Result: i}))
+	}
+	f.SumManyTimes.Enqueue(
+		fake.Send(&calculatorpb.SumManyTimesResponse{Result: 2}),
+		fake.Send(&calculatorpb.SumManyTimesResponse{Result: 3}),
+	)
+	c := cache.New(cfg)
+	h := harness.New(t, func(s *grpc.Server) {
+		calculatorpb.RegisterSumServiceServer(s, f.Service())
+	}, grpc.UnaryInterceptor(c.UnaryInterceptor), grpc.StreamInterceptor(c.StreamInterceptor))
+	return f, calculatorpb.NewSumServiceClient(h.Conn), c
+}
+
+func minute(methods ...string) config.Cache {
+	cfg := config.Cache{Methods: map[string]config.Duration{}}
+	for _, m := range methods {
+		cfg.Methods[m] = config.Duration(time.Minute)
+	}
+	return cfg
+}
+
+func sum(a, b int32) *calculatorpb.SumRequest {
+	return &calculatorpb.SumRequest{Sum: &calculatorpb.Sum{Sum1: a, Sum2: b}}
+}
+
+// call makes a SumData call with the given cache-control and returns the
+// result and the x-cache header.
+func call(t *testing.T, c calculatorpb.SumServiceClient, req *calculatorpb.SumRequest, control string) (int32, string) {
+	t.Helper()
+	ctx := context.Background()
+	if control != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, cache.ControlHeader, control)
+	}
+	var header metadata.MD
+	res, err := c.SumData(ctx, req, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("SumData: %v", err)
+	}
+	return res.GetResult(), first(header.Get(cache.StatusHeader))
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func TestUnary(t *testing.T) {
+	f, c, responses := start(t, minute(sumData))
+
+	if got, status := call(t, c, sum(1, 2), ""); got != 1 || status != "miss" {
+		t.Errorf("first call = %d, %q; want 1, miss", got, status)
+	}
+	if got, status := call(t, c, sum(1, 2), ""); got != 1 || status != "hit" {
+		t.Errorf("second call = %d, %q; want 1, hit", got, status)
+	}
+	if got, _ := call(t, c, sum(2, 1), ""); got != 2 {
+		t.Errorf("other request = %d, want 2", got)
+	}
+	if n := len(f.SumData.Calls()); n != 2 {
+		t.Errorf("handler ran %d times, want 2", n)
+	}
+	want := cache.Stats{Hits: 1, Misses: 2, Entries: 2, Bytes: responses.Stats().Bytes}
+	if got := responses.Stats(); got != want || got.Bytes == 0 {
+		t.Errorf("Stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestMethodNotCached(t *testing.T) {
+	f, c, _ := start(t, minute(sumManyTimes))
+
+	for i := 0; i < 2; i++ {
+		if _, status := call(t, c, sum(1, 2), ""); status != "" {
+			t.Errorf("x-cache = %q, want none", status)
+		}
+	}
+	if n := len(f.SumData.Calls()); n != 2 {
+		t.Errorf("handler ran %d times, want 2", n)
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	_, c, _ := start(t, minute(sumData))
+
+	call(t, c, sum(1, 2), "")
+	if got, status := call(t, c, sum(1, 2), "no-cache"); got != 2 || status != "miss" {
+		t.Errorf("no-cache = %d, %q; want 2, miss", got, status)
+	}
+	// no-cache stored the fresh response.
+	if got, _ := call(t, c, sum(1, 2), ""); got != 2 {
+		t.Errorf("after no-cache = %d, want 2", got)
+	}
+	if got, _ := call(t, c, sum(1, 2), "max-age=60, No-Store"); got != 3 {
+		t.Errorf("no-store = %d, want 3", got)
+	}
+	if got, _ := call(t, c, sum(1, 2), ""); got != 2 {
+		t.Errorf("after no-store = %d, want 2", got)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	_, c, _ := start(t, config.Cache{Methods: map[string]config.Duration{sumData: config.Duration(time.Millisecond)}})
+
+	call(t, c, sum(1, 2), "")
+	time.Sleep(10 * time.Millisecond)
+	if got, status := call(t, c, sum(1, 2), ""); got != 2 || status != "miss" {
+		t.Errorf("after expiry = %d, %q; want 2, miss", got, status)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	cfg := minute(sumData)
+	cfg.MaxEntries = 2
+	_, c, responses := start(t, cfg)
+
+	call(t, c, sum(1, 1), "") // 1
+	call(t, c, sum(2, 2), "") // 2
+	call(t, c, sum(1, 1), "") // hit, 2+2 is now least recently used
+	call(t, c, sum(3, 3), "") // 3, evicts 2+2
+
+	if got, _ := call(t, c, sum(1, 1), ""); got != 1 {
+		t.Errorf("1+1 = %d, want the cached 1", got)
+	}
+	if got, _ := call(t, c, sum(2, 2), ""); got != 4 {
+		t.Errorf("2+2 = %d, want a fresh 4", got)
+	}
+	if s := responses.Stats(); s.Evictions != 2 || s.Entries != 2 {
+		t.Errorf("Stats = %+v, want 2 evictions and 2 entries", s)
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	cfg := minute(sumData)
+	cfg.MaxBytes = 8
+	_, c, responses := start(t, cfg)
+
+	call(t, c, sum(1, 2), "")
+	if got, _ := call(t, c, sum(1, 2), ""); got != 2 {
+		t.Errorf("second call = %d, want 2: the entry is too large to keep", got)
+	}
+	if s := responses.Stats(); s.Entries != 0 {
+		t.Errorf("Entries = %d, want 0", s.Entries)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	_, c, responses := start(t, minute(sumData))
+
+	call(t, c, sum(1, 2), "")
+	responses.Update(minute(sumManyTimes))
+	if s := responses.Stats(); s.Entries != 0 || s.Bytes != 0 {
+		t.Errorf("Stats after Update = %+v, want empty", s)
+	}
+	if _, status := call(t, c, sum(1, 2), ""); status != "" {
+		t.Errorf("x-cache after Update = %q, want none", status)
+	}
+}
+
+func TestServerStream(t *testing.T) {
+	f, c, _ := start(t, minute(sumManyTimes))
+
+	for _, want := range []string{"miss", "hit"} {
+		var header metadata.MD
+		stream, err := c.SumManyTimes(context.Background(), &calculatorpb.SumManyTimesRequest{Total: 6}, grpc.Header(&header))
+		if err != nil {
+			t.Fatalf("SumManyTimes: %v", err)
+		}
+		var got []int32
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Recv: %v", err)
+			}
+			got = append(got, res.GetResult())
+		}
+		if !reflect.DeepEqual(got, []int32{2, 3}) {
+			t.Errorf("SumManyTimes = %v, want [2 3]", got)
+		}
+		if status := first(header.Get(cache.StatusHeader)); status != want {
+			t.Errorf("x-cache = %q, want %q", status, want)
+		}
+	}
+
+	calls := f.SumManyTimes.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("handler ran %d times, want 1", len(calls))
+	}
+	// The handler got the request read by the interceptor.
+	if req := calls[0].Requests[0].(*calculatorpb.SumManyTimesRequest); req.GetTotal() != 6 {
+		t.Errorf("handler got total %d, want 6", req.GetTotal())
+	}
+}
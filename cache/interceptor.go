@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"strings"
+)
+
+const (
+	// ControlHeader is the request metadata key carrying cache directives.
+	ControlHeader = "cache-control"
+	// StatusHeader is the response header telling whether the cache answered.
+	StatusHeader = "x-cache"
+)
+
+// directives returns whether the call may be answered from the cache and
+// whether its response may be stored, according to ControlHeader.
+func directives(ctx context.Context) (lookup, store bool) {
+	lookup, store = true, true
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get(ControlHeader) {
+		for _, d := range strings.Split(value, ",") {
+			switch strings.ToLower(strings.TrimSpace(d)) {
+			case "no-cache":
+				lookup = false
+			case "no-store":
+				lookup, store = false, false
+			}
+		}
+	}
+	return lookup, store
+}
+
+// requestKey is method followed by the canonical encoding of req: fields in
+// number order, map entries sorted and unknown fields dropped.
+func requestKey(method string, req proto.Message) (string, error) {
+	req = proto.Clone(req)
+	discardUnknown(req.ProtoReflect())
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	return method + "\x00" + string(data), nil
+}
+
+func discardUnknown(m protoreflect.Message) {
+	m.SetUnknown(nil)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len(); i++ {
+				discardUnknown(v.List().Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				discardUnknown(v.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			discardUnknown(v.Message())
+		}
+		return true
+	})
+}
+
+// UnaryInterceptor answers calls to cached methods from the cache.
+func (c *Cache) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ttl := c.ttl(info.FullMethod)
+	msg, ok := req.(proto.Message)
+	if ttl <= 0 || !ok {
+		return handler(ctx, req)
+	}
+	key, err := requestKey(info.FullMethod, msg)
+	if err != nil {
+		return handler(ctx, req)
+	}
+
+	lookup, store := directives(ctx)
+	if lookup {
+		if responses, ok := c.get(info.FullMethod, key); ok {
+			grpc.SetHeader(ctx, metadata.Pairs(StatusHeader, "hit"))
+			return responses[0], nil
+		}
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(StatusHeader, "miss"))
+	res, err := handler(ctx, req)
+	if out, ok := res.(proto.Message); ok && err == nil && store {
+		c.put(info.FullMethod, key, []proto.Message{proto.Clone(out)}, ttl)
+	}
+	return res, err
+}
+
+// StreamInterceptor answers server streaming calls to cached methods from
+// the cache. Other streams are passed through.
+func (c *Cache) StreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ttl := c.ttl(info.FullMethod)
+	if ttl <= 0 || info.IsClientStream || !info.IsServerStream {
+		return handler(srv, ss)
+	}
+	mt, err := inputType(info.FullMethod)
+	if err != nil {
+		return handler(srv, ss)
+	}
+
+	// The request is read here to look it up, and handed to the handler
+	// when it asks for it.
+	req := mt.New().Interface()
+	if err := ss.RecvMsg(req); err != nil {
+		return err
+	}
+	key, err := requestKey(info.FullMethod, req)
+	if err != nil {
+		return handler(srv, &recordingStream{ServerStream: ss, req: req})
+	}
+
+	lookup, store := directives(ss.Context())
+	if lookup {
+		if responses, ok := c.get(info.FullMethod, key); ok {
+			ss.SetHeader(metadata.Pairs(StatusHeader, "hit"))
+			for _, res := range responses {
+				if err := ss.SendMsg(res); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	ss.SetHeader(metadata.Pairs(StatusHeader, "miss"))
+	rs := &recordingStream{ServerStream: ss, req: req}
+	if err := handler(srv, rs); err != nil {
+		return err
+	}
+	if store {
+		c.put(info.FullMethod, key, rs.sent, ttl)
+	}
+	return nil
+}
+
+// recordingStream hands the handler the request read by the interceptor and
+// keeps copies of what the handler sends.
+type recordingStream struct {
+	grpc.ServerStream
+	req  proto.Message
+	sent []proto.Message
+}
+
+func (s *recordingStream) RecvMsg(m any) error {
+	if s.req == nil {
+		return s.ServerStream.RecvMsg(m)
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("cache: cannot receive into %T", m)
+	}
+	proto.Merge(msg, s.req)
+	s.req = nil
+	return nil
+}
+
+func (s *recordingStream) SendMsg(m any) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		s.sent = append(s.sent, proto.Clone(msg))
+	}
+	return nil
+}
+
+// inputType returns the request type of a full method name.
+func inputType(fullMethod string) (protoreflect.MessageType, error) {
+	name := strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", ".")
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, err
+	}
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("cache: %s is not a method", fullMethod)
+	}
+	return protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+}
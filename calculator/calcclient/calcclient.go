@@ -0,0 +1,112 @@
+// Package calcclient is a client library for calculator.v1.CalculatorService
+// with default deadlines, retries of Sum and typed errors, see rpcclient.
+package calcclient
+
+import (
+	"context"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/rpcclient"
+	"google.golang.org/grpc"
+	"io"
+)
+
+// Idempotent lists the methods that are safe to retry.
+var Idempotent = []string{"/calculator.v1.CalculatorService/Sum"}
+
+// Client calls CalculatorService. Errors are *rpcclient.Error.
+type Client struct {
+	cc   *grpc.ClientConn
+	pb   calculatorv1.CalculatorServiceClient
+	opts rpcclient.Options
+}
+
+// Dial connects to a calculator_server at addr.
+func Dial(ctx context.Context, addr string, opts rpcclient.Options) (*Client, error) {
+	cc, err := rpcclient.Dial(ctx, addr, opts, Idempotent...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{cc: cc, pb: calculatorv1.NewCalculatorServiceClient(cc), opts: opts}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.cc.Close()
+}
+
+// Raw returns the generated client on the same connection, for calls the
+// helpers below do not cover.
+func (c *Client) Raw() calculatorv1.CalculatorServiceClient {
+	return c.pb
+}
+
+// Sum returns a + b.
+func (c *Client) Sum(ctx context.Context, a, b int32) (int32, error) {
+	res, err := c.pb.Sum(ctx, &calculatorv1.SumRequest{Operands: &calculatorv1.Operands{A: a, B: b}})
+	if err != nil {
+		return 0, err
+	}
+	return res.GetResult(), nil
+}
+
+// Decompose calls fn with every prime factor of n as the server streams
+// them. An error from fn cancels the stream and is returned.
+func (c *Client) Decompose(ctx context.Context, n int32, fn func(int32) error) error {
+	ctx, cancel := c.streamContext(ctx)
+	defer cancel()
+
+	stream, err := c.pb.Decompose(ctx, &calculatorv1.DecomposeRequest{Number: n})
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(res.GetFactor()); err != nil {
+			return err
+		}
+	}
+}
+
+// Average streams numbers to the server and returns their mean.
+func (c *Client) Average(ctx context.Context, numbers []int32) (float64, error) {
+	ctx, cancel := c.streamContext(ctx)
+	defer cancel()
+
+	stream, err := c.pb.Average(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, n := range numbers {
+		if err := stream.Send(&calculatorv1.AverageRequest{Number: n}); err != nil {
+			// io.EOF means the server ended the call, CloseAndRecv has the reason.
+			if err == io.EOF {
+				break
+			}
+			return 0, err
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, err
+	}
+	return res.GetAverage(), nil
+}
+
+// FindMaximum opens the bidi stream. Its lifetime is the caller's: it is
+// bound to ctx only and gets no default deadline.
+func (c *Client) FindMaximum(ctx context.Context) (calculatorv1.CalculatorService_FindMaximumClient, error) {
+	return c.pb.FindMaximum(ctx)
+}
+
+func (c *Client) streamContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.opts.StreamTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.opts.StreamTimeout)
+}
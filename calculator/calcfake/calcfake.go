@@ -0,0 +1,103 @@
+// Package calcfake is a scriptable in-process SumService for tests of code
+// that uses calculatorpb.SumServiceClient. See package fake for scripts.
+//
+//	f, client := calcfake.Start(t)
+//	f.SumData.Enqueue(fake.Fail(status.Error(codes.Unavailable, "down")))
+//	... exercise code using client ...
+//	reqs := fake.Requests[*calculatorpb.SumRequest](f.SumData)
+package calcfake
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	"github.com/ferza17/grpc-course/fake"
+	"github.com/ferza17/grpc-course/harness"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+// Server implements calculatorpb.SumServiceServer with one fake.Method per RPC.
+type Server struct {
+	SumData      *fake.Method
+	SumManyTimes *fake.Method
+	AvgLongTimes *fake.Method
+	FindMaximum  *fake.Method
+}
+
+// New returns a Server without scripts.
+func New() *Server {
+	return &Server{
+		SumData:      fake.NewMethod("/calculator.SumService/SumData"),
+		SumManyTimes: fake.NewMethod("/calculator.SumService/SumManyTimes"),
+		AvgLongTimes: fake.NewMethod("/calculator.SumService/AvgLongTimes"),
+		FindMaximum:  fake.NewMethod("/calculator.SumService/FindMaximum"),
+	}
+}
+
+// Start serves a new Server on a bufconn listener for the duration of the
+// test and returns it with a client connected to it.
+func Start(tb testing.TB) (*Server, calculatorpb.SumServiceClient) {
+	tb.Helper()
+	f := New()
+	h := harness.New(tb, func(s *grpc.Server) { calculatorpb.RegisterSumServiceServer(s, f.Service()) })
+	return f, calculatorpb.NewSumServiceClient(h.Conn)
+}
+
+// Service returns the calculatorpb.SumServiceServer to register.
+func (f *Server) Service() calculatorpb.SumServiceServer {
+	return service{f: f}
+}
+
+// service adapts Server to the generated interface, whose method names
+// clash with the fields of Server.
+type service struct {
+	calculatorpb.UnimplementedSumServiceServer
+
+	f *Server
+}
+
+func (s service) SumData(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
+	res, err := fake.Unary(ctx, s.f.SumData, req)
+	if err != nil {
+		return nil, err
+	}
+	return fake.As[*calculatorpb.SumResponse](res)
+}
+
+func (s service) SumManyTimes(req *calculatorpb.SumManyTimesRequest, stream calculatorpb.SumService_SumManyTimesServer) error {
+	return fake.ServerStream(stream.Context(), s.f.SumManyTimes, req, func(m proto.Message) error {
+		out, err := fake.As[*calculatorpb.SumManyTimesResponse](m)
+		if err != nil {
+			return err
+		}
+		return stream.Send(out)
+	})
+}
+
+func (s service) AvgLongTimes(stream calculatorpb.SumService_AvgLongTimesServer) error {
+	res, err := fake.ClientStream(stream.Context(), s.f.AvgLongTimes, func() (proto.Message, error) {
+		return stream.Recv()
+	})
+	if err != nil {
+		return err
+	}
+	out, err := fake.As[*calculatorpb.AvgLongResponse](res)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(out)
+}
+
+func (s service) FindMaximum(stream calculatorpb.SumService_FindMaximumServer) error {
+	return fake.BiDi(stream.Context(), s.f.FindMaximum,
+		func() (proto.Message, error) { return stream.Recv() },
+		func(m proto.Message) error {
+			out, err := fake.As[*calculatorpb.FindMaximumResponse](m)
+			if err != nil {
+				return err
+			}
+			return stream.Send(out)
+		},
+	)
+}
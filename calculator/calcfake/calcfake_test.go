@@ -0,0 +1,64 @@
+package calcfake_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/calculator/calcfake"
+	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	"github.com/ferza17/grpc-course/fake"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestSumData(t *testing.T) {
+	f, c := calcfake.Start(t)
+	f.SumData.Enqueue(fake.Send(&calculatorpb.SumResponse{Result: 42}))
+
+	res, err := c.SumData(context.Background(), &calculatorpb.SumRequest{Sum: &calculatorpb.Sum{Sum1: 1, Sum2: 2}})
+	if err != nil || res.GetResult() != 42 {
+		t.Fatalf("SumData = %v, %v, want 42", res, err)
+	}
+	reqs := fake.Requests[*calculatorpb.SumRequest](f.SumData)
+	if len(reqs) != 1 || reqs[0].GetSum().GetSum1() != 1 {
+		t.Errorf("recorded requests = %v", reqs)
+	}
+}
+
+func TestAvgLongTimesError(t *testing.T) {
+	f, c := calcfake.Start(t)
+	f.AvgLongTimes.Enqueue(fake.Fail(status.Error(codes.InvalidArgument, "no numbers")))
+
+	stream, err := c.AvgLongTimes(context.Background())
+	if err != nil {
+		t.Fatalf("AvgLongTimes: %v", err)
+	}
+	if err := stream.Send(&calculatorpb.AvgLongRequest{Num: 3}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CloseAndRecv error = %v, want InvalidArgument", err)
+	}
+	if reqs := fake.Requests[*calculatorpb.AvgLongRequest](f.AvgLongTimes); len(reqs) != 1 || reqs[0].GetNum() != 3 {
+		t.Errorf("recorded requests = %v", reqs)
+	}
+}
+
+func TestFindMaximumFails(t *testing.T) {
+	f, c := calcfake.Start(t)
+	f.FindMaximum.Enqueue(
+		fake.Send(&calculatorpb.FindMaximumResponse{Maximum: 7}),
+		fake.Fail(status.Error(codes.Unavailable, "down")),
+	)
+
+	stream, err := c.FindMaximum(context.Background())
+	if err != nil {
+		t.Fatalf("FindMaximum: %v", err)
+	}
+	res, err := stream.Recv()
+	if err != nil || res.GetMaximum() != 7 {
+		t.Fatalf("Recv = %v, %v, want 7", res, err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Recv error = %v, want Unavailable", err)
+	}
+}
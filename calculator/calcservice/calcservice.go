@@ -0,0 +1,143 @@
+// Package calcservice implements CalculatorService, so it can be served by
+// calculator_server or embedded in other servers and tests.
+package calcservice
+
+import (
+	"context"
+	"fmt"
+	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/pacing"
+	"google.golang.org/grpc"
+	"io"
+	"log"
+	"os"
+)
+
+// Server implements calculator.v1.CalculatorService. Legacy serves it under
+// the old calculator.SumService name.
+type Server struct {
+	calculatorv1.UnimplementedCalculatorServiceServer
+
+	// Pacing spaces the Decompose messages, nil sends them back to back.
+	Pacing *pacing.Policy
+
+	// Out receives a line for every call served, nil means os.Stdout.
+	Out io.Writer
+}
+
+// New returns a Server paced by p.
+func New(p *pacing.Policy) *Server {
+	return &Server{Pacing: p}
+}
+
+// Register serves srv on s as calculator.v1.CalculatorService and, until its
+// clients have migrated, as calculator.SumService.
+func Register(s grpc.ServiceRegistrar, srv *Server) {
+	calculatorv1.RegisterCalculatorServiceServer(s, srv)
+	calculatorpb.RegisterSumServiceServer(s, Legacy(srv))
+}
+
+func (s *Server) printf(format string, args ...any) {
+	out := s.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	fmt.Fprintf(out, format, args...)
+}
+
+func (s *Server) Sum(ctx context.Context, req *calculatorv1.SumRequest) (*calculatorv1.SumResponse, error) {
+	s.printf("Sum function was invoked with: %v\n", req)
+	res := &calculatorv1.SumResponse{
+		Result: req.GetOperands().GetA() + req.GetOperands().GetB(),
+	}
+
+	return res, nil
+}
+
+func (s *Server) Decompose(req *calculatorv1.DecomposeRequest, stream calculatorv1.CalculatorService_DecomposeServer) error {
+	s.printf("Decompose was invoked with: %v\n", req)
+	pacer, err := s.Pacing.Pacer(stream.Context())
+	if err != nil {
+		return err
+	}
+	total := int(req.GetNumber())
+	divisor := 2
+
+	for total > 1 {
+		// Without pacing the loop is no longer throttled, so a prime total
+		// ends trial division at its square root rather than at itself.
+		if divisor*divisor > total {
+			divisor = total
+		}
+		if total%divisor == 0 {
+			if err := pacer.Wait(stream.Context()); err != nil {
+				return err
+			}
+			if err := stream.Send(&calculatorv1.DecomposeResponse{Factor: int32(divisor)}); err != nil {
+				log.Printf("Error when streaming: %v", err)
+				return err
+			}
+			total /= divisor
+		} else {
+			divisor++
+		}
+	}
+	return nil
+}
+
+func (s *Server) Average(stream calculatorv1.CalculatorService_AverageServer) error {
+	var total int32
+	var divider int
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			// End Of File
+
+			// Business Logic
+			var result float64
+			result = float64(total) / float64(divider)
+			// End
+
+			return stream.SendAndClose(&calculatorv1.AverageResponse{
+				Average: result,
+			})
+		}
+
+		if err != nil {
+			log.Printf("Unable to read client stream: %v", err)
+			return err
+		}
+
+		// divider +1 if not in end of file
+		total += req.GetNumber()
+		divider += 1
+	}
+}
+
+func (s *Server) FindMaximum(stream calculatorv1.CalculatorService_FindMaximumServer) error {
+	maximum := int32(0)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			log.Printf("Error While Reading stream: %v", err)
+			return err
+		}
+
+		// Business Logic
+		number := req.GetNumber()
+		if number > maximum {
+			maximum = number
+			if err := stream.Send(&calculatorv1.FindMaximumResponse{Maximum: maximum}); err != nil {
+				log.Printf("Error while sending stream: %v", err)
+				return err
+			}
+		}
+
+	}
+
+}
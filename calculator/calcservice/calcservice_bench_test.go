@@ -0,0 +1,95 @@
+package calcservice_test
+
+import (
+	"context"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"io"
+	"testing"
+)
+
+// The benchmarks run the handlers unpaced over bufconn, so they measure the
+// handlers and the gRPC stack without the network. The harness servers do
+// not print the calls.
+
+func BenchmarkSum(b *testing.B) {
+	c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(b).Conn)
+	req := &calculatorv1.SumRequest{Operands: &calculatorv1.Operands{A: 3, B: 10}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.Sum(context.Background(), req); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkDecompose measures whole calls decomposing 120 into five primes.
+func BenchmarkDecompose(b *testing.B) {
+	c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(b).Conn)
+	req := &calculatorv1.DecomposeRequest{Number: 120}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream, err := c.Decompose(context.Background(), req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkAverage measures whole calls of ten requests each.
+func BenchmarkAverage(b *testing.B) {
+	c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(b).Conn)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream, err := c.Average(context.Background())
+		if err != nil {
+			b.Fatal(err)
+		}
+		for j := int32(0); j < 10; j++ {
+			if err := stream.Send(&calculatorv1.AverageRequest{Number: j}); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if _, err := stream.CloseAndRecv(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFindMaximum measures round trips on one open stream. Every number
+// is a new maximum so every request is answered.
+func BenchmarkFindMaximum(b *testing.B) {
+	c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(b).Conn)
+	stream, err := c.FindMaximum(context.Background())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := stream.Send(&calculatorv1.FindMaximumRequest{Number: int32(i)}); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		b.Fatalf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,201 @@
+package calcservice_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/calculator/calcservice"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/pacing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// hourly waits an hour between streamed messages, so a stream can be
+// canceled after its first message.
+var hourly = pacing.New(config.Pacing{Default: config.Pace{Mode: pacing.Fixed, Interval: config.Duration(time.Hour)}})
+
+func TestSum(t *testing.T) {
+	c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(t).Conn)
+
+	tests := []struct {
+		name string
+		sum  *calculatorv1.Operands
+		want int32
+	}{
+		{name: "positive", sum: &calculatorv1.Operands{A: 3, B: 10}, want: 13},
+		{name: "negative", sum: &calculatorv1.Operands{A: -3, B: -10}, want: -13},
+		{name: "zero", sum: &calculatorv1.Operands{}, want: 0},
+		{name: "no sum", sum: nil, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := c.Sum(context.Background(), &calculatorv1.SumRequest{Operands: tt.sum})
+			if err != nil {
+				t.Fatalf("SumData: %v", err)
+			}
+			if res.GetResult() != tt.want {
+				t.Errorf("Sum = %d, want %d", res.GetResult(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(t).Conn)
+
+	tests := []struct {
+		name  string
+		total int32
+		want  []int32
+	}{
+		{name: "zero", total: 0, want: nil},
+		{name: "one", total: 1, want: nil},
+		{name: "negative", total: -12, want: nil},
+		{name: "prime", total: 13, want: []int32{13}},
+		{name: "composite", total: 120, want: []int32{2, 2, 2, 3, 5}},
+		{name: "square", total: 49, want: []int32{7, 7}},
+		{name: "large prime factor", total: 2 * 1000003, want: []int32{2, 1000003}},
+		{name: "largest prime", total: math.MaxInt32, want: []int32{math.MaxInt32}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.Decompose(context.Background(), &calculatorv1.DecomposeRequest{Number: tt.total})
+			if err != nil {
+				t.Fatalf("Decompose: %v", err)
+			}
+			var got []int32
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Recv: %v", err)
+				}
+				got = append(got, res.GetFactor())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decompose(%d) = %v, want %v", tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecomposeCancel(t *testing.T) {
+	h := harness.New(t, func(s *grpc.Server) {
+		calcservice.Register(s, calcservice.New(hourly))
+	})
+	c := calculatorv1.NewCalculatorServiceClient(h.Conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.Decompose(ctx, &calculatorv1.DecomposeRequest{Number: 120})
+	if err != nil {
+		t.Fatalf("Decompose: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("first Recv: %v", err)
+	}
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("Recv after cancel = %v, want Canceled", err)
+	}
+}
+
+func TestAverage(t *testing.T) {
+	c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(t).Conn)
+
+	tests := []struct {
+		name    string
+		numbers []int32
+		want    float64
+	}{
+		// Nothing to average over: the server divides zero by zero.
+		{name: "empty stream", numbers: nil, want: math.NaN()},
+		{name: "one", numbers: []int32{7}, want: 7},
+		{name: "many", numbers: []int32{1, 2, 3, 4}, want: 2.5},
+		{name: "negative", numbers: []int32{-1, -2}, want: -1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.Average(context.Background())
+			if err != nil {
+				t.Fatalf("Average: %v", err)
+			}
+			for _, n := range tt.numbers {
+				if err := stream.Send(&calculatorv1.AverageRequest{Number: n}); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if err != nil {
+				t.Fatalf("CloseAndRecv: %v", err)
+			}
+			got := res.GetAverage()
+			if math.IsNaN(tt.want) {
+				if !math.IsNaN(got) {
+					t.Errorf("Average = %v, want NaN", got)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Average = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindMaximum(t *testing.T) {
+	c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(t).Conn)
+
+	tests := []struct {
+		name    string
+		numbers []int32
+		want    []int32
+	}{
+		{name: "empty stream", numbers: nil, want: nil},
+		{name: "increasing", numbers: []int32{1, 5, 3, 6, 2, 20}, want: []int32{1, 5, 6, 20}},
+		// The maximum starts at zero, so negative numbers are never sent.
+		{name: "negative", numbers: []int32{-5, -9, -2, -3}, want: nil},
+		{name: "negative then positive", numbers: []int32{-5, 3, -2, 7}, want: []int32{3, 7}},
+		{name: "repeated", numbers: []int32{4, 4, 4}, want: []int32{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.FindMaximum(context.Background())
+			if err != nil {
+				t.Fatalf("FindMaximum: %v", err)
+			}
+			for _, n := range tt.numbers {
+				if err := stream.Send(&calculatorv1.FindMaximumRequest{Number: n}); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("CloseSend: %v", err)
+			}
+
+			var got []int32
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Recv: %v", err)
+				}
+				got = append(got, res.GetMaximum())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindMaximum(%v) = %v, want %v", tt.numbers, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package calcservice
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/legacy"
+	"google.golang.org/grpc"
+)
+
+// Legacy serves s under the pre-v1 name calculator.SumService, mapping
+// SumData to Sum, SumManyTimes to Decompose and AvgLongTimes to Average.
+// Requests and responses are converted to and from their wire compatible v1
+// messages.
+func Legacy(s calculatorv1.CalculatorServiceServer) calculatorpb.SumServiceServer {
+	return legacyServer{s: s}
+}
+
+type legacyServer struct {
+	calculatorpb.UnimplementedSumServiceServer
+
+	s calculatorv1.CalculatorServiceServer
+}
+
+func (l legacyServer) SumData(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
+	var v1req calculatorv1.SumRequest
+	if err := legacy.Convert(&v1req, req); err != nil {
+		return nil, err
+	}
+	v1res, err := l.s.Sum(ctx, &v1req)
+	if err != nil {
+		return nil, err
+	}
+	var res calculatorpb.SumResponse
+	if err := legacy.Convert(&res, v1res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (l legacyServer) SumManyTimes(req *calculatorpb.SumManyTimesRequest, stream calculatorpb.SumService_SumManyTimesServer) error {
+	var v1req calculatorv1.DecomposeRequest
+	if err := legacy.Convert(&v1req, req); err != nil {
+		return err
+	}
+	return l.s.Decompose(&v1req, decomposeStream{stream})
+}
+
+func (l legacyServer) AvgLongTimes(stream calculatorpb.SumService_AvgLongTimesServer) error {
+	return l.s.Average(averageStream{stream})
+}
+
+func (l legacyServer) FindMaximum(stream calculatorpb.SumService_FindMaximumServer) error {
+	return l.s.FindMaximum(findMaximumStream{stream})
+}
+
+// The stream adapters hand v1 messages straight to the underlying stream,
+// which encodes them exactly like their legacy counterparts.
+
+type decomposeStream struct {
+	grpc.ServerStream
+}
+
+func (s decomposeStream) Send(res *calculatorv1.DecomposeResponse) error {
+	return s.SendMsg(res)
+}
+
+type averageStream struct {
+	grpc.ServerStream
+}
+
+func (s averageStream) Recv() (*calculatorv1.AverageRequest, error) {
+	req := new(calculatorv1.AverageRequest)
+	if err := s.RecvMsg(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s averageStream) SendAndClose(res *calculatorv1.AverageResponse) error {
+	return s.SendMsg(res)
+}
+
+type findMaximumStream struct {
+	grpc.ServerStream
+}
+
+func (s findMaximumStream) Recv() (*calculatorv1.FindMaximumRequest, error) {
+	req := new(calculatorv1.FindMaximumRequest)
+	if err := s.RecvMsg(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s findMaximumStream) Send(res *calculatorv1.FindMaximumResponse) error {
+	return s.SendMsg(res)
+}
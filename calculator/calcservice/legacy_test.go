@@ -0,0 +1,90 @@
+package calcservice_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	"github.com/ferza17/grpc-course/harness"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// The legacy service shares the implementation, so these tests only check
+// that every message makes it through the adapter.
+
+func TestLegacySumData(t *testing.T) {
+	c := calculatorpb.NewSumServiceClient(harness.Calculator(t).Conn)
+	res, err := c.SumData(context.Background(), &calculatorpb.SumRequest{Sum: &calculatorpb.Sum{Sum1: 3, Sum2: 10}})
+	if err != nil {
+		t.Fatalf("SumData: %v", err)
+	}
+	if res.GetResult() != 13 {
+		t.Errorf("SumData = %d, want 13", res.GetResult())
+	}
+}
+
+func TestLegacySumManyTimes(t *testing.T) {
+	c := calculatorpb.NewSumServiceClient(harness.Calculator(t).Conn)
+	stream, err := c.SumManyTimes(context.Background(), &calculatorpb.SumManyTimesRequest{Total: 120})
+	if err != nil {
+		t.Fatalf("SumManyTimes: %v", err)
+	}
+	var got []int32
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		got = append(got, res.GetResult())
+	}
+	if want := []int32{2, 2, 2, 3, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SumManyTimes(120) = %v, want %v", got, want)
+	}
+}
+
+func TestLegacyAvgLongTimes(t *testing.T) {
+	c := calculatorpb.NewSumServiceClient(harness.Calculator(t).Conn)
+	stream, err := c.AvgLongTimes(context.Background())
+	if err != nil {
+		t.Fatalf("AvgLongTimes: %v", err)
+	}
+	for _, n := range []int32{1, 2, 3, 4} {
+		if err := stream.Send(&calculatorpb.AvgLongRequest{Num: n}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv: %v", err)
+	}
+	if res.GetResult() != 2.5 {
+		t.Errorf("AvgLongTimes = %v, want 2.5", res.GetResult())
+	}
+}
+
+func TestLegacyFindMaximum(t *testing.T) {
+	c := calculatorpb.NewSumServiceClient(harness.Calculator(t).Conn)
+	stream, err := c.FindMaximum(context.Background())
+	if err != nil {
+		t.Fatalf("FindMaximum: %v", err)
+	}
+	for _, n := range []int32{3, 7} {
+		if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: n}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if res.GetMaximum() != n {
+			t.Errorf("FindMaximum = %d, want %d", res.GetMaximum(), n)
+		}
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+}
@@ -2,115 +2,179 @@ package main
 
 import (
 	"context"
+	"expvar"
+	"flag"
 	"fmt"
+	"github.com/ferza17/grpc-course/audit"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/cache"
+	"github.com/ferza17/grpc-course/calculator/calcservice"
 	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/deadline"
+	"github.com/ferza17/grpc-course/descriptor"
+	"github.com/ferza17/grpc-course/gateway"
+	"github.com/ferza17/grpc-course/idempotency"
+	"github.com/ferza17/grpc-course/legacy"
+	"github.com/ferza17/grpc-course/multiplex"
+	"github.com/ferza17/grpc-course/pacing"
+	"github.com/ferza17/grpc-course/ratelimit"
+	"github.com/ferza17/grpc-course/transport"
+	"github.com/ferza17/grpc-course/validate"
+	"github.com/ferza17/grpc-course/web"
 	"google.golang.org/grpc"
-	"io"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
-type server struct {
-}
+var configPath = flag.String("config", "", "path to a JSON config file")
 
-func (*server) SumData(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
-	fmt.Printf("SumData function was invoked with: %v\n", req)
-	res := &calculatorpb.SumResponse{
-		Result: req.Sum.GetSum1() + req.Sum.GetSum2(),
+func main() {
+	flag.Parse()
+	defaults := config.Config{
+		Addr:       "0.0.0.0:50052",
+		HTTPAddr:   "0.0.0.0:8082",
+		Reflection: true,
+		Gateway:    true,
+		GRPCWeb:    true,
+		Transport:  config.Transport{Keepalive: transport.DefaultKeepalive},
+	}
+	cfg, err := config.Load(*configPath, defaults)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := pacing.Validate(cfg.Pacing); err != nil {
+		log.Fatalf("Invalid config: %v", err)
 	}
 
-	return res, nil
-}
-
-func (*server) SumManyTimes(req *calculatorpb.SumManyTimesRequest, stream calculatorpb.SumService_SumManyTimesServer) error {
-	fmt.Printf("SumManyTimes was invoked with: %v", req)
-	total := int(req.GetTotal())
-	divisor := 2
+	fmt.Println("About to start Server...")
+	lis, err := transport.Listen(cfg.Addr, cfg.Transport)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
 
-	for total > 1 {
-		if total%divisor == 0 {
-			if err := stream.Send(&calculatorpb.SumManyTimesResponse{Result: int32(divisor)}); err != nil {
-				log.Fatalf("Error when streaming: %v", err)
-				return err
-			}
-			total /= divisor
-		} else {
-			divisor++
-			fmt.Printf("Divisor has increased to %v", divisor)
+	authenticator, err := auth.New(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+	authenticator.TrustListener(lis.Addr())
+	tracker := legacy.NewTracker(legacy.Successors)
+	limiter := ratelimit.New(cfg.RateLimit)
+	pacer := pacing.New(cfg.Pacing)
+	dedup, err := idempotency.New(cfg.Idempotency)
+	if err != nil {
+		log.Fatalf("Failed to open idempotency store: %v", err)
+	}
+	auditor, err := audit.New(cfg.Audit)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer auditor.Close()
+	responses := cache.New(cfg.Cache)
+	go config.Watch(*configPath, defaults, 5*time.Second, nil, func(cfg config.Config) {
+		log.Println("Reloading rate limits")
+		limiter.Update(cfg.RateLimit)
+		log.Println("Reloading cache settings")
+		responses.Update(cfg.Cache)
+		if err := pacing.Validate(cfg.Pacing); err != nil {
+			log.Printf("Ignoring pacing change: %v", err)
+			return
 		}
-		time.Sleep(time.Second * 1)
+		log.Println("Reloading pacing")
+		pacer.Update(cfg.Pacing)
+	})
+
+	opts := append(transport.ServerOptions(cfg.Transport),
+		grpc.ChainUnaryInterceptor(
+			authenticator.UnaryInterceptor,
+			tracker.UnaryInterceptor,
+			auditor.UnaryInterceptor,
+			limiter.UnaryInterceptor,
+			validate.UnaryInterceptor,
+			dedup.UnaryInterceptor,
+			responses.UnaryInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			authenticator.StreamInterceptor,
+			tracker.StreamInterceptor,
+			auditor.StreamInterceptor,
+			limiter.StreamInterceptor,
+			validate.StreamInterceptor,
+			responses.StreamInterceptor,
+			deadline.StreamInterceptor(time.Duration(cfg.MaxStreamLifetime)),
+		),
+	)
+	s := grpc.NewServer(opts...)
+	calcservice.Register(s, calcservice.New(pacer))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	if cfg.Reflection {
+		reflection.Register(s)
 	}
-	return nil
-}
-
-func (*server) AvgLongTimes(stream calculatorpb.SumService_AvgLongTimesServer) error {
-	var total int32
-	var divider int
-
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			// End Of File
 
-			// Business Logic
-			var result float64
-			result = float64(total) / float64(divider)
-			// End
-
-			return stream.SendAndClose(&calculatorpb.AvgLongResponse{
-				Result: result,
-			})
-		}
+	srv := &multiplex.Server{GRPC: s, Health: healthServer, Grace: time.Duration(cfg.ShutdownGrace)}
+	if cfg.HTTPAddr != "" || cfg.SinglePort {
+		mux := http.NewServeMux()
+		mux.Handle("/descriptor", descriptor.Handler(s))
+		mux.Handle("/debug/vars", expvar.Handler())
+		// There is no Prometheus exporter yet, /metrics is the expvar JSON.
+		mux.Handle("/metrics", expvar.Handler())
+		mux.Handle("/healthz", multiplex.Healthz(healthServer))
+		if cfg.Gateway || cfg.GRPCWeb {
+			// The HTTP handlers reach the services through the gRPC listener
+			// so every interceptor applies to HTTP traffic as well.
+			cc, err := transport.DialListener(lis)
+			if err != nil {
+				log.Fatalf("Failed to dial HTTP backend: %v", err)
+			}
+			defer cc.Close()
 
-		if err != nil {
-			log.Fatalf("Unable to read client stream: %v", err)
-			return err
+			services := []protoreflect.ServiceDescriptor{
+				calculatorv1.File_calculator_v1_calculator_proto.Services().ByName("CalculatorService"),
+				calculatorpb.File_calculator_calculatorpb_calculator_proto.Services().ByName("SumService"),
+			}
+			var handler http.Handler = http.NotFoundHandler()
+			if cfg.Gateway {
+				mux.Handle("/openapi.json", gateway.OpenAPIHandler("calculator", services...))
+				gw := gateway.New(cc, services...)
+				gw.MaxBodySize = int64(cfg.Transport.MaxRecvMsgSize)
+				handler = gw
+			}
+			if cfg.GRPCWeb {
+				opts := web.Options{AllowedOrigins: cfg.WebOrigins, MaxMessageSize: int64(cfg.Transport.MaxRecvMsgSize)}
+				mux.Handle(web.WebSocketPrefix+"/", web.WebSocket(cc, opts, services...))
+				handler = web.GRPCWeb(cc, opts, handler)
+			}
+			mux.Handle("/", handler)
 		}
-
-		// divider +1 if not in end of file
-		total += req.GetNum()
-		divider += 1
+		srv.HTTP = mux
 	}
-}
-
-func (*server) FindMaximum(stream calculatorpb.SumService_FindMaximumServer) error {
-	maximum := int32(0)
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
-		if err != nil {
-			log.Fatalf("Error While Reading stream: %v", err)
-			return err
-		}
 
-		// Business Logic
-		number := req.GetNumber()
-		if number > maximum {
-			maximum = number
-			if err := stream.Send(&calculatorpb.FindMaximumResponse{Maximum: maximum}); err != nil {
-				log.Fatalf("Error while sending stream: %v", err)
-				return err
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if cfg.SinglePort {
+		err = srv.ServeSingle(ctx, lis)
+	} else {
+		var httpLis net.Listener
+		if srv.HTTP != nil {
+			if httpLis, err = transport.Listen(cfg.HTTPAddr, cfg.Transport); err != nil {
+				log.Fatalf("Failed to listen for HTTP: %v", err)
 			}
 		}
-
+		err = srv.Serve(ctx, lis, httpLis)
 	}
-
-}
-
-func main() {
-	fmt.Println("About to start Server...")
-	lis, err := net.Listen("tcp", "0.0.0.0:50052")
 	if err != nil {
-		log.Fatalf("Failed to Listen: %v", err)
+		log.Fatalf("Failed to serve: %v", err)
 	}
-	s := grpc.NewServer()
-	calculatorpb.RegisterSumServiceServer(s, &server{})
-
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("Failed to server: %v", err)
-	}
-
+	log.Println("Server stopped")
 }
@@ -1,20 +1,18 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.14.0
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
 // source: calculator/calculatorpb/calculator.proto
 
 package calculatorpb
 
 import (
-	context "context"
-	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
+	_ "github.com/ferza17/grpc-course/validate/v1"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -25,21 +23,18 @@ const (
 )
 
 type Sum struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sum1          int32                  `protobuf:"varint,1,opt,name=sum1,proto3" json:"sum1,omitempty"`
+	Sum2          int32                  `protobuf:"varint,2,opt,name=sum2,proto3" json:"sum2,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Sum1 int32 `protobuf:"varint,1,opt,name=sum1,proto3" json:"sum1,omitempty"`
-	Sum2 int32 `protobuf:"varint,2,opt,name=sum2,proto3" json:"sum2,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Sum) Reset() {
 	*x = Sum{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Sum) String() string {
@@ -50,7 +45,7 @@ func (*Sum) ProtoMessage() {}
 
 func (x *Sum) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -80,20 +75,17 @@ func (x *Sum) GetSum2() int32 {
 }
 
 type SumRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sum           *Sum                   `protobuf:"bytes,1,opt,name=sum,proto3" json:"sum,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Sum *Sum `protobuf:"bytes,1,opt,name=sum,proto3" json:"sum,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SumRequest) Reset() {
 	*x = SumRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *SumRequest) String() string {
@@ -104,7 +96,7 @@ func (*SumRequest) ProtoMessage() {}
 
 func (x *SumRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -127,20 +119,17 @@ func (x *SumRequest) GetSum() *Sum {
 }
 
 type SumResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        int32                  `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Result int32 `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SumResponse) Reset() {
 	*x = SumResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *SumResponse) String() string {
@@ -151,7 +140,7 @@ func (*SumResponse) ProtoMessage() {}
 
 func (x *SumResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -174,20 +163,17 @@ func (x *SumResponse) GetResult() int32 {
 }
 
 type SumManyTimesRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Total int32 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SumManyTimesRequest) Reset() {
 	*x = SumManyTimesRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *SumManyTimesRequest) String() string {
@@ -198,7 +184,7 @@ func (*SumManyTimesRequest) ProtoMessage() {}
 
 func (x *SumManyTimesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -221,20 +207,17 @@ func (x *SumManyTimesRequest) GetTotal() int32 {
 }
 
 type SumManyTimesResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        int32                  `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Result int32 `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SumManyTimesResponse) Reset() {
 	*x = SumManyTimesResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *SumManyTimesResponse) String() string {
@@ -245,7 +228,7 @@ func (*SumManyTimesResponse) ProtoMessage() {}
 
 func (x *SumManyTimesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -268,20 +251,17 @@ func (x *SumManyTimesResponse) GetResult() int32 {
 }
 
 type AvgLongRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Num           int32                  `protobuf:"varint,1,opt,name=num,proto3" json:"num,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Num int32 `protobuf:"varint,1,opt,name=num,proto3" json:"num,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AvgLongRequest) Reset() {
 	*x = AvgLongRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AvgLongRequest) String() string {
@@ -292,7 +272,7 @@ func (*AvgLongRequest) ProtoMessage() {}
 
 func (x *AvgLongRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -315,20 +295,17 @@ func (x *AvgLongRequest) GetNum() int32 {
 }
 
 type AvgLongResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        float64                `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Result float64 `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AvgLongResponse) Reset() {
 	*x = AvgLongResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AvgLongResponse) String() string {
@@ -339,7 +316,7 @@ func (*AvgLongResponse) ProtoMessage() {}
 
 func (x *AvgLongResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -362,20 +339,17 @@ func (x *AvgLongResponse) GetResult() float64 {
 }
 
 type FindMaximumRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FindMaximumRequest) Reset() {
 	*x = FindMaximumRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *FindMaximumRequest) String() string {
@@ -386,7 +360,7 @@ func (*FindMaximumRequest) ProtoMessage() {}
 
 func (x *FindMaximumRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[7]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -409,20 +383,17 @@ func (x *FindMaximumRequest) GetNumber() int32 {
 }
 
 type FindMaximumResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Maximum       int32                  `protobuf:"varint,1,opt,name=maximum,proto3" json:"maximum,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Maximum int32 `protobuf:"varint,1,opt,name=maximum,proto3" json:"maximum,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FindMaximumResponse) Reset() {
 	*x = FindMaximumResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *FindMaximumResponse) String() string {
@@ -433,7 +404,7 @@ func (*FindMaximumResponse) ProtoMessage() {}
 
 func (x *FindMaximumResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -457,75 +428,51 @@ func (x *FindMaximumResponse) GetMaximum() int32 {
 
 var File_calculator_calculatorpb_calculator_proto protoreflect.FileDescriptor
 
-var file_calculator_calculatorpb_calculator_proto_rawDesc = []byte{
-	0x0a, 0x28, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x63, 0x61, 0x6c,
-	0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x70, 0x62, 0x2f, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c,
-	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x63, 0x61, 0x6c, 0x63,
-	0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x2d, 0x0a, 0x03, 0x53, 0x75, 0x6d, 0x12, 0x12, 0x0a,
-	0x04, 0x73, 0x75, 0x6d, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x75, 0x6d,
-	0x31, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x75, 0x6d, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x04, 0x73, 0x75, 0x6d, 0x32, 0x22, 0x2f, 0x0a, 0x0a, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x75,
-	0x6d, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x22, 0x25, 0x0a, 0x0b, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x2b, 0x0a,
-	0x13, 0x53, 0x75, 0x6d, 0x4d, 0x61, 0x6e, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x2e, 0x0a, 0x14, 0x53, 0x75,
-	0x6d, 0x4d, 0x61, 0x6e, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x22, 0x0a, 0x0e, 0x41, 0x76,
-	0x67, 0x4c, 0x6f, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03,
-	0x6e, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6e, 0x75, 0x6d, 0x22, 0x29,
-	0x0a, 0x0f, 0x41, 0x76, 0x67, 0x4c, 0x6f, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x01, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x2c, 0x0a, 0x12, 0x46, 0x69, 0x6e,
-	0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x2f, 0x0a, 0x13, 0x46, 0x69, 0x6e, 0x64, 0x4d,
-	0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
-	0x0a, 0x07, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x07, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x32, 0xc4, 0x02, 0x0a, 0x0a, 0x53, 0x75, 0x6d,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3c, 0x0a, 0x07, 0x53, 0x75, 0x6d, 0x44, 0x61,
-	0x74, 0x61, 0x12, 0x16, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e,
-	0x53, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x63, 0x61, 0x6c,
-	0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x0c, 0x53, 0x75, 0x6d, 0x4d, 0x61, 0x6e, 0x79,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74,
-	0x6f, 0x72, 0x2e, 0x53, 0x75, 0x6d, 0x4d, 0x61, 0x6e, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61,
-	0x74, 0x6f, 0x72, 0x2e, 0x53, 0x75, 0x6d, 0x4d, 0x61, 0x6e, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4b, 0x0a, 0x0c,
-	0x41, 0x76, 0x67, 0x4c, 0x6f, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x63,
-	0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x41, 0x76, 0x67, 0x4c, 0x6f, 0x6e,
-	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75,
-	0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x41, 0x76, 0x67, 0x4c, 0x6f, 0x6e, 0x67, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x54, 0x0a, 0x0b, 0x46, 0x69, 0x6e,
-	0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x12, 0x1e, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75,
-	0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75,
-	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75,
-	0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75,
-	0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42,
-	0x19, 0x5a, 0x17, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x63, 0x61,
-	0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
-}
+const file_calculator_calculatorpb_calculator_proto_rawDesc = "" +
+	"\n" +
+	"(calculator/calculatorpb/calculator.proto\x12\n" +
+	"calculator\x1a\x1avalidate/v1/validate.proto\"-\n" +
+	"\x03Sum\x12\x12\n" +
+	"\x04sum1\x18\x01 \x01(\x05R\x04sum1\x12\x12\n" +
+	"\x04sum2\x18\x02 \x01(\x05R\x04sum2\"7\n" +
+	"\n" +
+	"SumRequest\x12)\n" +
+	"\x03sum\x18\x01 \x01(\v2\x0f.calculator.SumB\x06\xc2\xf3\x18\x02\b\x01R\x03sum\"%\n" +
+	"\vSumResponse\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\x05R\x06result\"3\n" +
+	"\x13SumManyTimesRequest\x12\x1c\n" +
+	"\x05total\x18\x01 \x01(\x05B\x06\xc2\xf3\x18\x02(\x00R\x05total\".\n" +
+	"\x14SumManyTimesResponse\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\x05R\x06result\"\"\n" +
+	"\x0eAvgLongRequest\x12\x10\n" +
+	"\x03num\x18\x01 \x01(\x05R\x03num\")\n" +
+	"\x0fAvgLongResponse\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\x01R\x06result\",\n" +
+	"\x12FindMaximumRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\"/\n" +
+	"\x13FindMaximumResponse\x12\x18\n" +
+	"\amaximum\x18\x01 \x01(\x05R\amaximum2\xc4\x02\n" +
+	"\n" +
+	"SumService\x12<\n" +
+	"\aSumData\x12\x16.calculator.SumRequest\x1a\x17.calculator.SumResponse\"\x00\x12U\n" +
+	"\fSumManyTimes\x12\x1f.calculator.SumManyTimesRequest\x1a .calculator.SumManyTimesResponse\"\x000\x01\x12K\n" +
+	"\fAvgLongTimes\x12\x1a.calculator.AvgLongRequest\x1a\x1b.calculator.AvgLongResponse\"\x00(\x01\x12T\n" +
+	"\vFindMaximum\x12\x1e.calculator.FindMaximumRequest\x1a\x1f.calculator.FindMaximumResponse\"\x00(\x010\x01B8Z6github.com/ferza17/grpc-course/calculator/calculatorpbb\x06proto3"
 
 var (
 	file_calculator_calculatorpb_calculator_proto_rawDescOnce sync.Once
-	file_calculator_calculatorpb_calculator_proto_rawDescData = file_calculator_calculatorpb_calculator_proto_rawDesc
+	file_calculator_calculatorpb_calculator_proto_rawDescData []byte
 )
 
 func file_calculator_calculatorpb_calculator_proto_rawDescGZIP() []byte {
 	file_calculator_calculatorpb_calculator_proto_rawDescOnce.Do(func() {
-		file_calculator_calculatorpb_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(file_calculator_calculatorpb_calculator_proto_rawDescData)
+		file_calculator_calculatorpb_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_calculator_calculatorpb_calculator_proto_rawDesc), len(file_calculator_calculatorpb_calculator_proto_rawDesc)))
 	})
 	return file_calculator_calculatorpb_calculator_proto_rawDescData
 }
 
 var file_calculator_calculatorpb_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
-var file_calculator_calculatorpb_calculator_proto_goTypes = []interface{}{
+var file_calculator_calculatorpb_calculator_proto_goTypes = []any{
 	(*Sum)(nil),                  // 0: calculator.Sum
 	(*SumRequest)(nil),           // 1: calculator.SumRequest
 	(*SumResponse)(nil),          // 2: calculator.SumResponse
@@ -558,121 +505,11 @@ func file_calculator_calculatorpb_calculator_proto_init() {
 	if File_calculator_calculatorpb_calculator_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_calculator_calculatorpb_calculator_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Sum); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_calculator_calculatorpb_calculator_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SumRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_calculator_calculatorpb_calculator_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SumResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_calculator_calculatorpb_calculator_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SumManyTimesRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_calculator_calculatorpb_calculator_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SumManyTimesResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_calculator_calculatorpb_calculator_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AvgLongRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_calculator_calculatorpb_calculator_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AvgLongResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_calculator_calculatorpb_calculator_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FindMaximumRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_calculator_calculatorpb_calculator_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FindMaximumResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_calculator_calculatorpb_calculator_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_calculator_calculatorpb_calculator_proto_rawDesc), len(file_calculator_calculatorpb_calculator_proto_rawDesc)),
 			NumEnums:      0,
 			NumMessages:   9,
 			NumExtensions: 0,
@@ -683,297 +520,6 @@ func file_calculator_calculatorpb_calculator_proto_init() {
 		MessageInfos:      file_calculator_calculatorpb_calculator_proto_msgTypes,
 	}.Build()
 	File_calculator_calculatorpb_calculator_proto = out.File
-	file_calculator_calculatorpb_calculator_proto_rawDesc = nil
 	file_calculator_calculatorpb_calculator_proto_goTypes = nil
 	file_calculator_calculatorpb_calculator_proto_depIdxs = nil
 }
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
-
-// SumServiceClient is the client API for SumService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type SumServiceClient interface {
-	// UNARY API
-	SumData(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error)
-	//Server Streaming API
-	SumManyTimes(ctx context.Context, in *SumManyTimesRequest, opts ...grpc.CallOption) (SumService_SumManyTimesClient, error)
-	//Client Streaming API
-	AvgLongTimes(ctx context.Context, opts ...grpc.CallOption) (SumService_AvgLongTimesClient, error)
-	// Bi Directional Streaming API
-	FindMaximum(ctx context.Context, opts ...grpc.CallOption) (SumService_FindMaximumClient, error)
-}
-
-type sumServiceClient struct {
-	cc grpc.ClientConnInterface
-}
-
-func NewSumServiceClient(cc grpc.ClientConnInterface) SumServiceClient {
-	return &sumServiceClient{cc}
-}
-
-func (c *sumServiceClient) SumData(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error) {
-	out := new(SumResponse)
-	err := c.cc.Invoke(ctx, "/calculator.SumService/SumData", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *sumServiceClient) SumManyTimes(ctx context.Context, in *SumManyTimesRequest, opts ...grpc.CallOption) (SumService_SumManyTimesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_SumService_serviceDesc.Streams[0], "/calculator.SumService/SumManyTimes", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &sumServiceSumManyTimesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type SumService_SumManyTimesClient interface {
-	Recv() (*SumManyTimesResponse, error)
-	grpc.ClientStream
-}
-
-type sumServiceSumManyTimesClient struct {
-	grpc.ClientStream
-}
-
-func (x *sumServiceSumManyTimesClient) Recv() (*SumManyTimesResponse, error) {
-	m := new(SumManyTimesResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func (c *sumServiceClient) AvgLongTimes(ctx context.Context, opts ...grpc.CallOption) (SumService_AvgLongTimesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_SumService_serviceDesc.Streams[1], "/calculator.SumService/AvgLongTimes", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &sumServiceAvgLongTimesClient{stream}
-	return x, nil
-}
-
-type SumService_AvgLongTimesClient interface {
-	Send(*AvgLongRequest) error
-	CloseAndRecv() (*AvgLongResponse, error)
-	grpc.ClientStream
-}
-
-type sumServiceAvgLongTimesClient struct {
-	grpc.ClientStream
-}
-
-func (x *sumServiceAvgLongTimesClient) Send(m *AvgLongRequest) error {
-	return x.ClientStream.SendMsg(m)
-}
-
-func (x *sumServiceAvgLongTimesClient) CloseAndRecv() (*AvgLongResponse, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	m := new(AvgLongResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func (c *sumServiceClient) FindMaximum(ctx context.Context, opts ...grpc.CallOption) (SumService_FindMaximumClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_SumService_serviceDesc.Streams[2], "/calculator.SumService/FindMaximum", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &sumServiceFindMaximumClient{stream}
-	return x, nil
-}
-
-type SumService_FindMaximumClient interface {
-	Send(*FindMaximumRequest) error
-	Recv() (*FindMaximumResponse, error)
-	grpc.ClientStream
-}
-
-type sumServiceFindMaximumClient struct {
-	grpc.ClientStream
-}
-
-func (x *sumServiceFindMaximumClient) Send(m *FindMaximumRequest) error {
-	return x.ClientStream.SendMsg(m)
-}
-
-func (x *sumServiceFindMaximumClient) Recv() (*FindMaximumResponse, error) {
-	m := new(FindMaximumResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-// SumServiceServer is the server API for SumService service.
-type SumServiceServer interface {
-	// UNARY API
-	SumData(context.Context, *SumRequest) (*SumResponse, error)
-	//Server Streaming API
-	SumManyTimes(*SumManyTimesRequest, SumService_SumManyTimesServer) error
-	//Client Streaming API
-	AvgLongTimes(SumService_AvgLongTimesServer) error
-	// Bi Directional Streaming API
-	FindMaximum(SumService_FindMaximumServer) error
-}
-
-// UnimplementedSumServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedSumServiceServer struct {
-}
-
-func (*UnimplementedSumServiceServer) SumData(context.Context, *SumRequest) (*SumResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SumData not implemented")
-}
-func (*UnimplementedSumServiceServer) SumManyTimes(*SumManyTimesRequest, SumService_SumManyTimesServer) error {
-	return status.Errorf(codes.Unimplemented, "method SumManyTimes not implemented")
-}
-func (*UnimplementedSumServiceServer) AvgLongTimes(SumService_AvgLongTimesServer) error {
-	return status.Errorf(codes.Unimplemented, "method AvgLongTimes not implemented")
-}
-func (*UnimplementedSumServiceServer) FindMaximum(SumService_FindMaximumServer) error {
-	return status.Errorf(codes.Unimplemented, "method FindMaximum not implemented")
-}
-
-func RegisterSumServiceServer(s *grpc.Server, srv SumServiceServer) {
-	s.RegisterService(&_SumService_serviceDesc, srv)
-}
-
-func _SumService_SumData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SumRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SumServiceServer).SumData(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/calculator.SumService/SumData",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SumServiceServer).SumData(ctx, req.(*SumRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _SumService_SumManyTimes_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SumManyTimesRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(SumServiceServer).SumManyTimes(m, &sumServiceSumManyTimesServer{stream})
-}
-
-type SumService_SumManyTimesServer interface {
-	Send(*SumManyTimesResponse) error
-	grpc.ServerStream
-}
-
-type sumServiceSumManyTimesServer struct {
-	grpc.ServerStream
-}
-
-func (x *sumServiceSumManyTimesServer) Send(m *SumManyTimesResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _SumService_AvgLongTimes_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(SumServiceServer).AvgLongTimes(&sumServiceAvgLongTimesServer{stream})
-}
-
-type SumService_AvgLongTimesServer interface {
-	SendAndClose(*AvgLongResponse) error
-	Recv() (*AvgLongRequest, error)
-	grpc.ServerStream
-}
-
-type sumServiceAvgLongTimesServer struct {
-	grpc.ServerStream
-}
-
-func (x *sumServiceAvgLongTimesServer) SendAndClose(m *AvgLongResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func (x *sumServiceAvgLongTimesServer) Recv() (*AvgLongRequest, error) {
-	m := new(AvgLongRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func _SumService_FindMaximum_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(SumServiceServer).FindMaximum(&sumServiceFindMaximumServer{stream})
-}
-
-type SumService_FindMaximumServer interface {
-	Send(*FindMaximumResponse) error
-	Recv() (*FindMaximumRequest, error)
-	grpc.ServerStream
-}
-
-type sumServiceFindMaximumServer struct {
-	grpc.ServerStream
-}
-
-func (x *sumServiceFindMaximumServer) Send(m *FindMaximumResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func (x *sumServiceFindMaximumServer) Recv() (*FindMaximumRequest, error) {
-	m := new(FindMaximumRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-var _SumService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "calculator.SumService",
-	HandlerType: (*SumServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "SumData",
-			Handler:    _SumService_SumData_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "SumManyTimes",
-			Handler:       _SumService_SumManyTimes_Handler,
-			ServerStreams: true,
-		},
-		{
-			StreamName:    "AvgLongTimes",
-			Handler:       _SumService_AvgLongTimes_Handler,
-			ClientStreams: true,
-		},
-		{
-			StreamName:    "FindMaximum",
-			Handler:       _SumService_FindMaximum_Handler,
-			ServerStreams: true,
-			ClientStreams: true,
-		},
-	},
-	Metadata: "calculator/calculatorpb/calculator.proto",
-}
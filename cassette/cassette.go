@@ -0,0 +1,170 @@
+// Package cassette records the calls a gRPC proxy forwards, with every
+// message and when it was sent, and plays them back without the backend. It
+// works on message bytes, see package rawcodec, so it serves any method of
+// any service:
+//
+//	rec, err := cassette.Record(cc, "greet.cassette")
+//	s := grpc.NewServer(cassette.ServerOptions(rec.Handler)...)
+//
+//	c, err := cassette.Load("greet.cassette")
+//	s := grpc.NewServer(cassette.ServerOptions(cassette.NewPlayer(c, 0).Handler)...)
+//
+// A cassette is a file of JSON lines, one Interaction per call in the order
+// the calls ended. Credentials are not recorded.
+package cassette
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/rawcodec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"os"
+	"strings"
+)
+
+// Kinds of Event.
+const (
+	// Request is a message from the client.
+	Request = "request"
+	// CloseSend is the client closing its side of the stream.
+	CloseSend = "close_send"
+	// Response is a message from the server.
+	Response = "response"
+)
+
+// Event is one step of a call.
+type Event struct {
+	// At is the time since the call started.
+	At      config.Duration `json:"at"`
+	Kind    string          `json:"kind"`
+	Message []byte          `json:"message,omitempty"`
+}
+
+// Interaction is one recorded call.
+type Interaction struct {
+	Method string `json:"method"`
+	// Metadata is the request metadata without credentials and transport
+	// headers. It is not used to match calls on playback.
+	Metadata metadata.MD `json:"metadata,omitempty"`
+	Header   metadata.MD `json:"header,omitempty"`
+	Events   []Event     `json:"events"`
+	Trailer  metadata.MD `json:"trailer,omitempty"`
+	Code     string      `json:"code"`
+	Message  string      `json:"message,omitempty"`
+	// Details are the status details, each a marshaled google.protobuf.Any.
+	Details [][]byte `json:"details,omitempty"`
+	// Duration is the time since the call started when its status arrived.
+	Duration config.Duration `json:"duration"`
+}
+
+// Cassette is the calls of a cassette file.
+type Cassette struct {
+	Interactions []*Interaction
+}
+
+// Load reads the cassette at path.
+func Load(path string) (*Cassette, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &Cassette{}
+	lines := bufio.NewScanner(f)
+	lines.Buffer(nil, 64<<20)
+	for n := 1; lines.Scan(); n++ {
+		in := &Interaction{}
+		if err := json.Unmarshal(lines.Bytes(), in); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+		}
+		c.Interactions = append(c.Interactions, in)
+	}
+	return c, lines.Err()
+}
+
+// ServerOptions make a server pass the calls of every service not registered
+// on it to handler with raw messages.
+func ServerOptions(handler grpc.StreamHandler) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnknownServiceHandler(handler),
+		grpc.ForceServerCodec(rawcodec.Codec{}),
+	}
+}
+
+// setStatus stores st in the interaction.
+func (in *Interaction) setStatus(st *status.Status) {
+	in.Code = st.Code().String()
+	in.Message = st.Message()
+	for _, d := range st.Proto().GetDetails() {
+		data, err := proto.Marshal(d)
+		if err == nil {
+			in.Details = append(in.Details, data)
+		}
+	}
+}
+
+// err returns the recorded status as an error, nil for OK.
+func (in *Interaction) err() error {
+	code := codes.Unknown
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c.String() == in.Code {
+			code = c
+		}
+	}
+	st := status.New(code, in.Message).Proto()
+	for _, data := range in.Details {
+		d := &anypb.Any{}
+		if err := proto.Unmarshal(data, d); err == nil {
+			st.Details = append(st.Details, d)
+		}
+	}
+	return status.ErrorProto(st)
+}
+
+// omitted are the transport headers, set anew on every hop.
+var omitted = map[string]bool{
+	"content-type":         true,
+	"grpc-accept-encoding": true,
+	"user-agent":           true,
+}
+
+// credentials are the request metadata keys forwarded but not recorded.
+var credentials = map[string]bool{
+	"authorization":   true,
+	auth.APIKeyHeader: true,
+	"cookie":          true,
+}
+
+// forwarded returns md without transport headers.
+func forwarded(md metadata.MD) metadata.MD {
+	out := metadata.MD{}
+	for key, values := range md {
+		if !omitted[key] && !strings.HasPrefix(key, ":") {
+			out[key] = values
+		}
+	}
+	return out
+}
+
+// recorded returns md without credentials.
+func recorded(md metadata.MD) metadata.MD {
+	out := metadata.MD{}
+	for key, values := range md {
+		if !credentials[key] {
+			out[key] = values
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
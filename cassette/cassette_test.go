@@ -0,0 +1,302 @@
+package cassette_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/ferza17/grpc-course/cassette"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func greeting(name string) *greetpb.Greeting {
+	return &greetpb.Greeting{FirstName: name}
+}
+
+// session makes a call of every shape, and a failing one, and returns what
+// the client saw.
+func session(t *testing.T, c greetpb.GreatServiceClient) []string {
+	t.Helper()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	var got []string
+
+	res, err := c.Greet(ctx, &greetpb.GreatRequest{Greeting: greeting("Ann")})
+	if err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	got = append(got, res.GetResult())
+
+	_, err = c.Greet(ctx, &greetpb.GreatRequest{})
+	st := status.Convert(err)
+	if len(st.Details()) != 1 {
+		t.Fatalf("Greet without greeting = %v, want a status with details", err)
+	}
+	br := st.Details()[0].(*errdetails.BadRequest)
+	got = append(got, fmt.Sprintf("%s %s", st.Code(), br.GetFieldViolations()[0].GetField()))
+
+	many, err := c.GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{Greeting: greeting("Bo")})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	for {
+		res, err := many.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		got = append(got, res.GetResult())
+	}
+
+	long, err := c.LongGreet(ctx)
+	if err != nil {
+		t.Fatalf("LongGreet: %v", err)
+	}
+	for _, name := range []string{"Cy", "Di"} {
+		if err := long.Send(&greetpb.LongGreetRequest{Greeting: greeting(name)}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	lres, err := long.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv: %v", err)
+	}
+	got = append(got, lres.GetResult())
+
+	every, err := c.GreetEveryone(ctx)
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	for _, name := range []string{"Ed", "Flo"} {
+		if err := every.Send(&greetpb.GreetEveryoneRequest{Greeting: greeting(name)}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		res, err := every.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		got = append(got, res.GetResult())
+	}
+	every.CloseSend()
+	if _, err := every.Recv(); err != io.EOF {
+		t.Fatalf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+	return got
+}
+
+// record runs session through a Recorder in front of GreetService and returns
+// the cassette path and what the client saw.
+func record(t *testing.T) (string, []string) {
+	backend := harness.Greet(t, grpc.UnaryInterceptor(validate.UnaryInterceptor))
+	path := filepath.Join(t.TempDir(), "greet.cassette")
+	rec, err := cassette.Record(backend.Conn, path)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	proxy := harness.New(t, func(*grpc.Server) {}, cassette.ServerOptions(rec.Handler)...)
+	got := session(t, greetpb.NewGreatServiceClient(proxy.Conn))
+	proxy.Server.GracefulStop()
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return path, got
+}
+
+func play(t *testing.T, path string, speed float64) (*cassette.Player, *grpc.ClientConn) {
+	c, err := cassette.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	p := cassette.NewPlayer(c, speed)
+	h := harness.New(t, func(*grpc.Server) {}, cassette.ServerOptions(p.Handler)...)
+	return p, h.Conn
+}
+
+func TestRecordAndPlay(t *testing.T) {
+	path, want := record(t)
+	if len(want) != 15 {
+		t.Fatalf("recorded session = %q", want)
+	}
+
+	c, err := cassette.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(c.Interactions) != 5 {
+		t.Fatalf("cassette has %d calls, want 5", len(c.Interactions))
+	}
+	for _, in := range c.Interactions {
+		if len(in.Metadata.Get("authorization")) > 0 {
+			t.Errorf("%s recorded authorization", in.Method)
+		}
+	}
+
+	p, cc := play(t, path, 0)
+	if got := session(t, greetpb.NewGreatServiceClient(cc)); !reflect.DeepEqual(got, want) {
+		t.Errorf("played session = %q, want %q", got, want)
+	}
+	if n := len(p.Unplayed()); n != 0 {
+		t.Errorf("%d calls not played", n)
+	}
+}
+
+func TestPlayNoMatch(t *testing.T) {
+	path, _ := record(t)
+	_, cc := play(t, path, 0)
+	c := greetpb.NewGreatServiceClient(cc)
+
+	if _, err := c.Greet(context.Background(), &greetpb.GreatRequest{Greeting: greeting("Zed")}); status.Code(err) != codes.NotFound {
+		t.Errorf("Greet of unrecorded request = %v, want NotFound", err)
+	}
+	if _, err := c.Greet(context.Background(), &greetpb.GreatRequest{Greeting: greeting("Ann")}); err != nil {
+		t.Errorf("Greet of recorded request: %v", err)
+	}
+	// Every recorded call is played once.
+	if _, err := c.Greet(context.Background(), &greetpb.GreatRequest{Greeting: greeting("Ann")}); status.Code(err) != codes.NotFound {
+		t.Errorf("Greet played again = %v, want NotFound", err)
+	}
+}
+
+func TestPlaySpeed(t *testing.T) {
+	msg := []byte{}
+	c := &cassette.Cassette{Interactions: []*cassette.Interaction{{
+		Method: "/greet.GreatService/Greet",
+		Events: []cassette.Event{
+			{Kind: cassette.Request, Message: msg},
+			{Kind: cassette.CloseSend},
+			{At: config.Duration(100 * time.Millisecond), Kind: cassette.Response, Message: msg},
+		},
+		Code:     "OK",
+		Duration: config.Duration(100 * time.Millisecond),
+	}}}
+
+	for _, tt := range []struct {
+		speed    float64
+		min, max time.Duration
+	}{
+		{speed: 1, min: 100 * time.Millisecond, max: time.Second},
+		{speed: 4, min: 25 * time.Millisecond, max: 90 * time.Millisecond},
+		{speed: 0, max: 90 * time.Millisecond},
+	} {
+		p := cassette.NewPlayer(c, tt.speed)
+		h := harness.New(t, func(*grpc.Server) {}, cassette.ServerOptions(p.Handler)...)
+		start := time.Now()
+		if _, err := greetpb.NewGreatServiceClient(h.Conn).Greet(context.Background(), &greetpb.GreatRequest{}); err != nil {
+			t.Fatalf("speed %v: Greet: %v", tt.speed, err)
+		}
+		if d := time.Since(start); d < tt.min || d > tt.max {
+			t.Errorf("speed %v: Greet took %v, want between %v and %v", tt.speed, d, tt.min, tt.max)
+		}
+	}
+}
+
+func TestPlayUnrecorded(t *testing.T) {
+	path, _ := record(t)
+	_, cc := play(t, path, 0)
+	c := greetpb.NewGreatServiceClient(cc)
+
+	stream, err := c.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{Greeting: greeting("Ann")})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Errorf("GreetManyTimes of unrecorded request = %v, want NotFound", err)
+	}
+	err = cc.Invoke(context.Background(), "/greet.GreatService/Missing", &greetpb.GreatRequest{}, &greetpb.GreetResponse{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("unrecorded method = %v, want Unimplemented", err)
+	}
+}
+
+func TestPlayConcurrentCallsClaimOnce(t *testing.T) {
+	msg := []byte{}
+	c := &cassette.Cassette{Interactions: []*cassette.Interaction{{
+		Method: "/greet.GreatService/Greet",
+		Events: []cassette.Event{
+			{Kind: cassette.Request, Message: msg},
+			{Kind: cassette.CloseSend},
+			{At: config.Duration(50 * time.Millisecond), Kind: cassette.Response, Message: msg},
+		},
+		Code: "OK",
+	}}}
+	p := cassette.NewPlayer(c, 1)
+	h := harness.New(t, func(*grpc.Server) {}, cassette.ServerOptions(p.Handler)...)
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := greetpb.NewGreatServiceClient(h.Conn).Greet(context.Background(), &greetpb.GreatRequest{})
+			errs <- err
+		}()
+	}
+	got := map[codes.Code]int{}
+	for i := 0; i < 2; i++ {
+		got[status.Code(<-errs)]++
+	}
+	if want := map[codes.Code]int{codes.OK: 1, codes.NotFound: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+}
+
+func TestPlayFollowsTheClaimedCall(t *testing.T) {
+	event := func(kind string, m proto.Message) cassette.Event {
+		data, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cassette.Event{Kind: kind, Message: data}
+	}
+	request := func(name string) cassette.Event {
+		return event(cassette.Request, &greetpb.GreetEveryoneRequest{Greeting: greeting(name)})
+	}
+	response := func(result string) cassette.Event {
+		return event(cassette.Response, &greetpb.GreetEveryoneResponse{Result: result})
+	}
+	c := &cassette.Cassette{Interactions: []*cassette.Interaction{
+		{
+			Method: "/greet.GreatService/GreetEveryone",
+			Events: []cassette.Event{request("Ann"), response("first"), request("Bo"), {Kind: cassette.CloseSend}},
+			Code:   "OK",
+		},
+		{
+			Method: "/greet.GreatService/GreetEveryone",
+			Events: []cassette.Event{request("Ann"), response("other"), request("Cy"), {Kind: cassette.CloseSend}},
+			Code:   "OK",
+		},
+	}}
+	p := cassette.NewPlayer(c, 0)
+	h := harness.New(t, func(*grpc.Server) {}, cassette.ServerOptions(p.Handler)...)
+	client := greetpb.NewGreatServiceClient(h.Conn)
+
+	// The first response tells the recorded calls apart, so the first is
+	// claimed to send it and the call has to go on as that one did.
+	stream, err := client.GreetEveryone(context.Background())
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	stream.Send(&greetpb.GreetEveryoneRequest{Greeting: greeting("Ann")})
+	if res, err := stream.Recv(); err != nil || res.GetResult() != "first" {
+		t.Fatalf("Recv = %v, %v; want first", res, err)
+	}
+	stream.Send(&greetpb.GreetEveryoneRequest{Greeting: greeting("Cy")})
+	stream.CloseSend()
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Errorf("Recv after diverging = %v, want NotFound", err)
+	}
+	if n := len(p.Unplayed()); n != 2 {
+		t.Errorf("%d calls not played, want 2: a failed call leaves its recorded call", n)
+	}
+}
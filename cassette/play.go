@@ -0,0 +1,226 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Player answers calls with the recorded ones, without a backend.
+//
+// A call is answered by a recorded call of its method that has not been
+// played yet and whose requests, compared byte for byte, are the same. The
+// player steps through the events of the recorded calls still matching: it
+// reads a message or the end of the requests from the client where the client
+// sent one, and sends the recorded responses, header, trailer and status. As
+// soon as one recorded call is left, or the ones left would answer
+// differently, the first of them is claimed for the call, so concurrent calls
+// never play the same one. A call that no recorded call matches fails with
+// NotFound, or Unimplemented when its method was never recorded.
+type Player struct {
+	speed   float64
+	methods map[string]bool
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	claimed      map[*Interaction]bool
+}
+
+// NewPlayer returns a Player of the calls in c. Responses are sent speed times
+// as fast as they were recorded: 1 keeps the recorded timing, 0 sends them as
+// soon as they are due in the sequence.
+func NewPlayer(c *Cassette, speed float64) *Player {
+	p := &Player{
+		speed:        speed,
+		methods:      map[string]bool{},
+		interactions: append([]*Interaction(nil), c.Interactions...),
+		claimed:      map[*Interaction]bool{},
+	}
+	for _, in := range c.Interactions {
+		p.methods[in.Method] = true
+	}
+	return p
+}
+
+// Unplayed returns the recorded calls that were not played.
+func (p *Player) Unplayed() []*Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var list []*Interaction
+	for _, in := range p.interactions {
+		if !p.claimed[in] {
+			list = append(list, in)
+		}
+	}
+	return list
+}
+
+// next returns the first recorded call of method, not claimed by another
+// call, whose events start with the events seen so far, and whether it was
+// claimed for the call: once it is the only one left, or the ones left would
+// not play the next event alike.
+func (p *Player) next(method string, seen []Event) (*Interaction, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var candidates []*Interaction
+	for _, in := range p.interactions {
+		if in.Method == method && !p.claimed[in] && startsWith(in, seen) {
+			candidates = append(candidates, in)
+		}
+	}
+	switch {
+	case len(candidates) == 0 && len(seen) == 0:
+		return nil, false, status.Errorf(codes.NotFound, "cassette: every recorded call of %s was played", method)
+	case len(candidates) == 0:
+		return nil, false, status.Errorf(codes.NotFound, "cassette: no recorded call of %s matches its requests", method)
+	case len(candidates) > 1 && agree(candidates, len(seen)):
+		return candidates[0], false, nil
+	}
+	p.claimed[candidates[0]] = true
+	return candidates[0], true, nil
+}
+
+// release makes in playable again after a call claimed it but failed to
+// play it.
+func (p *Player) release(in *Interaction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.claimed, in)
+}
+
+// startsWith reports whether the events of in start with seen.
+func startsWith(in *Interaction, seen []Event) bool {
+	if len(in.Events) < len(seen) {
+		return false
+	}
+	for i, ev := range seen {
+		if in.Events[i].Kind != ev.Kind || !bytes.Equal(in.Events[i].Message, ev.Message) {
+			return false
+		}
+	}
+	return true
+}
+
+// agree reports whether the candidates behave alike at event i: all wait for
+// the client, or all send the same response with the same header.
+func agree(candidates []*Interaction, i int) bool {
+	first := candidates[0]
+	if i >= len(first.Events) {
+		return false
+	}
+	if first.Events[i].Kind != Response {
+		for _, in := range candidates[1:] {
+			if i >= len(in.Events) || in.Events[i].Kind == Response {
+				return false
+			}
+		}
+		return true
+	}
+	for _, in := range candidates[1:] {
+		if i >= len(in.Events) || in.Events[i].Kind != Response ||
+			!bytes.Equal(in.Events[i].Message, first.Events[i].Message) || !reflect.DeepEqual(in.Header, first.Header) {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler plays a recorded call, see ServerOptions.
+func (p *Player) Handler(_ any, ss grpc.ServerStream) (err error) {
+	method, ok := grpc.MethodFromServerStream(ss)
+	if !ok {
+		return status.Error(codes.Internal, "cassette: no method in stream")
+	}
+	if !p.methods[method] {
+		return status.Errorf(codes.Unimplemented, "cassette: no recorded call of %s", method)
+	}
+
+	var (
+		start = time.Now()
+		seen  []Event
+		// in is the recorded call played, or until it is claimed the first
+		// of those still matching, which all play the next event alike.
+		in                 *Interaction
+		claimed, headerSet bool
+	)
+	defer func() {
+		// A call ending early, or mismatching, leaves its recorded call to
+		// another.
+		if claimed && err != nil && len(seen) < len(in.Events) {
+			p.release(in)
+		}
+	}()
+	for {
+		if !claimed {
+			if in, claimed, err = p.next(method, seen); err != nil {
+				return err
+			}
+		}
+		if len(seen) == len(in.Events) {
+			break
+		}
+
+		ev := in.Events[len(seen)]
+		if ev.Kind == Response {
+			if err := p.wait(ss.Context(), start, time.Duration(ev.At)); err != nil {
+				return err
+			}
+			if !headerSet {
+				ss.SetHeader(in.Header)
+				headerSet = true
+			}
+			if err := ss.SendMsg(&ev.Message); err != nil {
+				return err
+			}
+			seen = append(seen, ev)
+			continue
+		}
+
+		var msg []byte
+		kind := Request
+		if err := ss.RecvMsg(&msg); err == io.EOF {
+			kind = CloseSend
+		} else if err != nil {
+			return err
+		}
+		if claimed && (ev.Kind != kind || !bytes.Equal(ev.Message, msg)) {
+			return status.Errorf(codes.NotFound, "cassette: no recorded call of %s matches its requests", method)
+		}
+		seen = append(seen, Event{Kind: kind, Message: msg})
+	}
+
+	if err := p.wait(ss.Context(), start, time.Duration(in.Duration)); err != nil {
+		return err
+	}
+	if !headerSet {
+		ss.SetHeader(in.Header)
+	}
+	ss.SetTrailer(in.Trailer)
+	return in.err()
+}
+
+// wait sleeps until at, scaled by the speed, since start.
+func (p *Player) wait(ctx context.Context, start time.Time, at time.Duration) error {
+	if p.speed <= 0 {
+		return nil
+	}
+	d := time.Duration(float64(at)/p.speed) - time.Since(start)
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
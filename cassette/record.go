@@ -0,0 +1,127 @@
+package cassette
+
+import (
+	"encoding/json"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Recorder forwards calls to a backend and appends them to a cassette.
+type Recorder struct {
+	cc grpc.ClientConnInterface
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+// Record creates the cassette at path, replacing any, for calls forwarded to
+// cc.
+func Record(cc grpc.ClientConnInterface, path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{cc: cc, f: f}, nil
+}
+
+// Close closes the cassette file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+// recording is a call in progress. Requests and responses are relayed
+// concurrently.
+type recording struct {
+	start time.Time
+
+	mu sync.Mutex
+	in Interaction
+}
+
+func (c *recording) add(kind string, msg []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.in.Events = append(c.in.Events, Event{At: config.Duration(time.Since(c.start)), Kind: kind, Message: msg})
+}
+
+// Handler forwards a call to the backend and records it, see ServerOptions.
+func (r *Recorder) Handler(_ any, ss grpc.ServerStream) error {
+	method, ok := grpc.MethodFromServerStream(ss)
+	if !ok {
+		return status.Error(codes.Internal, "cassette: no method in stream")
+	}
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	c := &recording{start: time.Now(), in: Interaction{Method: method, Metadata: recorded(forwarded(md)), Events: []Event{}}}
+	err := proxy.Forward(&recordingStream{ServerStream: ss, c: c}, r.cc, method)
+	r.write(c, err)
+	return err
+}
+
+// recordingStream records what proxy.Forward relays.
+type recordingStream struct {
+	grpc.ServerStream
+	c *recording
+}
+
+func (s *recordingStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		s.c.add(CloseSend, nil)
+	case err == nil:
+		s.c.add(Request, *m.(*[]byte))
+	}
+	return err
+}
+
+func (s *recordingStream) SendMsg(m any) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.c.add(Response, *m.(*[]byte))
+	return nil
+}
+
+func (s *recordingStream) SetHeader(md metadata.MD) error {
+	s.c.mu.Lock()
+	s.c.in.Header = md
+	s.c.mu.Unlock()
+	return s.ServerStream.SetHeader(md)
+}
+
+func (s *recordingStream) SetTrailer(md metadata.MD) {
+	s.c.mu.Lock()
+	s.c.in.Trailer = md
+	s.c.mu.Unlock()
+	s.ServerStream.SetTrailer(md)
+}
+
+// write appends the call, ended with err, to the cassette. A call that cannot
+// be recorded is logged and not failed for it.
+func (r *Recorder) write(c *recording, err error) {
+	c.mu.Lock()
+	c.in.setStatus(status.Convert(err))
+	c.in.Duration = config.Duration(time.Since(c.start))
+	data, merr := json.Marshal(&c.in)
+	c.mu.Unlock()
+
+	if merr == nil {
+		r.mu.Lock()
+		_, merr = r.f.Write(append(data, '\n'))
+		r.mu.Unlock()
+	}
+	if merr != nil {
+		log.Printf("cassette: recording %s: %v", c.in.Method, merr)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var calcCommands = []command{
+	{name: "sum", usage: "sum A B", run: calcSum},
+	{name: "decompose", aliases: []string{"server-stream"}, usage: "decompose N", run: calcDecompose},
+	{name: "average", aliases: []string{"avg", "client-stream"}, usage: "average [--input FILE] [N...]", run: calcAverage},
+	{name: "max", aliases: []string{"bidi"}, usage: "max [--input FILE] [N...]", run: calcMax},
+	{name: "repl", usage: "repl (interactive FindMaximum)", run: calcREPL},
+}
+
+func parseInt32(s string) (int32, error) {
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, usageError{fmt.Sprintf("%q is not a 32-bit integer", s)}
+	}
+	return int32(n), nil
+}
+
+// eachNumber is eachInput for numbers, a line may hold several of them.
+func eachNumber(args []string, path string, fn func(int32) error) error {
+	return eachInput(args, path, func(s string) error {
+		for _, field := range strings.Fields(s) {
+			n, err := parseInt32(field)
+			if err != nil {
+				return err
+			}
+			if err := fn(n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func calcSum(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	if len(args) != 2 {
+		return usageError{"sum takes exactly two numbers"}
+	}
+	a, err := parseInt32(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := parseInt32(args[1])
+	if err != nil {
+		return err
+	}
+
+	res, err := calculatorv1.NewCalculatorServiceClient(cc).Sum(ctx, &calculatorv1.SumRequest{
+		Operands: &calculatorv1.Operands{A: a, B: b},
+	})
+	if err != nil {
+		return err
+	}
+	return out.print(res, strconv.Itoa(int(res.GetResult())))
+}
+
+func calcDecompose(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	if len(args) != 1 {
+		return usageError{"decompose takes exactly one number"}
+	}
+	total, err := parseInt32(args[0])
+	if err != nil {
+		return err
+	}
+
+	stream, err := calculatorv1.NewCalculatorServiceClient(cc).Decompose(ctx, &calculatorv1.DecomposeRequest{Number: total})
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := out.print(res, strconv.Itoa(int(res.GetFactor()))); err != nil {
+			return err
+		}
+	}
+}
+
+func calcAverage(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	path, rest, err := inputFlags("average", args)
+	if err != nil {
+		return err
+	}
+
+	stream, err := calculatorv1.NewCalculatorServiceClient(cc).Average(ctx)
+	if err != nil {
+		return err
+	}
+	err = eachNumber(rest, path, func(n int32) error {
+		return stream.Send(&calculatorv1.AverageRequest{Number: n})
+	})
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return out.print(res, strconv.FormatFloat(res.GetAverage(), 'g', -1, 64))
+}
+
+func calcMax(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	path, rest, err := inputFlags("max", args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := calculatorv1.NewCalculatorServiceClient(cc).FindMaximum(ctx)
+	if err != nil {
+		return err
+	}
+
+	sendErr := make(chan error, 1)
+	go func() {
+		err := eachNumber(rest, path, func(n int32) error {
+			return stream.Send(&calculatorv1.FindMaximumRequest{Number: n})
+		})
+		if err != nil && err != io.EOF {
+			sendErr <- err
+			cancel()
+			return
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return <-sendErr
+		}
+		if err != nil {
+			select {
+			case serr := <-sendErr:
+				if serr != nil {
+					return serr
+				}
+			default:
+			}
+			return err
+		}
+		if err := out.print(res, strconv.Itoa(int(res.GetMaximum()))); err != nil {
+			return err
+		}
+	}
+}
+
+func calcREPL(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	if len(args) > 0 {
+		return usageError{"repl takes no arguments"}
+	}
+
+	client := calculatorv1.NewCalculatorServiceClient(cc)
+	return runREPL(ctx, replCall{
+		name: "calc",
+		open: func(ctx context.Context) (grpc.ClientStream, error) {
+			return client.FindMaximum(ctx)
+		},
+		parse: func(line string) (proto.Message, error) {
+			n, err := parseInt32(line)
+			if err != nil {
+				return nil, err
+			}
+			return &calculatorv1.FindMaximumRequest{Number: n}, nil
+		},
+		response: func() proto.Message { return &calculatorv1.FindMaximumResponse{} },
+		text: func(m proto.Message) string {
+			return strconv.Itoa(int(m.(*calculatorv1.FindMaximumResponse).GetMaximum()))
+		},
+	}, out)
+}
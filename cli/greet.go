@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"flag"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var greetCommands = []command{
+	{name: "unary", aliases: []string{"greet"}, usage: "unary --first NAME [--last NAME] [--user ID]", run: greetUnary},
+	{name: "server-stream", aliases: []string{"many"}, usage: "server-stream --first NAME [--last NAME]", run: greetServerStream},
+	{name: "client-stream", aliases: []string{"long"}, usage: "client-stream [--input FILE] [NAME...]", run: greetClientStream},
+	{name: "bidi", aliases: []string{"everyone"}, usage: "bidi [--input FILE] [NAME...]", run: greetBiDi},
+	{name: "repl", usage: "repl (interactive GreetEveryone)", run: greetREPL},
+	{name: "profile-create", usage: "profile-create --user ID [--name NAME] [--locale TAG] [--pronouns P] [--tz ZONE]", run: profileCreate},
+	{name: "profile-get", usage: "profile-get ID", run: profileGet},
+	{name: "profile-update", usage: "profile-update --user ID [--name NAME] [--locale TAG] [--pronouns P] [--tz ZONE]", run: profileUpdate},
+	{name: "profile-delete", usage: "profile-delete ID", run: profileDelete},
+	{name: "profile-list", aliases: []string{"profiles"}, usage: "profile-list [--page-size N]", run: profileList},
+}
+
+// greetingFlags parses args with fs, to which it adds the name flags.
+func greetingFlags(fs *flag.FlagSet, args []string) (*greetv1.Greeting, error) {
+	first := fs.String("first", "", "first name")
+	last := fs.String("last", "", "last name")
+	if err := fs.Parse(args); err != nil {
+		return nil, usageError{err.Error()}
+	}
+	if *first == "" {
+		return nil, usageError{"--first is required"}
+	}
+	return &greetv1.Greeting{FirstName: *first, LastName: *last}, nil
+}
+
+// inputFlags parses the --input flag of args. Flags end at the first
+// number, so negative numbers such as "calc max -5 3" are inputs.
+func inputFlags(name string, args []string) (path string, rest []string, err error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	input := fs.String("input", "-", `file with one input per line, "-" for stdin`)
+	end := len(args)
+	for i, arg := range args {
+		if _, err := strconv.ParseFloat(arg, 64); err == nil && (i == 0 || args[i-1] != "-input" && args[i-1] != "--input") {
+			end = i
+			break
+		}
+	}
+	if err := fs.Parse(args[:end]); err != nil {
+		return "", nil, usageError{err.Error()}
+	}
+	return *input, append(fs.Args(), args[end:]...), nil
+}
+
+// parseGreeting accepts either a Greeting as JSON or "First [Last]".
+func parseGreeting(s string) (*greetv1.Greeting, error) {
+	g := &greetv1.Greeting{}
+	if strings.HasPrefix(s, "{") {
+		if err := protojson.Unmarshal([]byte(s), g); err != nil {
+			return nil, usageError{"invalid greeting " + s + ": " + err.Error()}
+		}
+		return g, nil
+	}
+
+	names := strings.SplitN(s, " ", 2)
+	g.FirstName = names[0]
+	if len(names) == 2 {
+		g.LastName = strings.TrimSpace(names[1])
+	}
+	return g, nil
+}
+
+func greetUnary(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	fs := flag.NewFlagSet("unary", flag.ContinueOnError)
+	user := fs.String("user", "", "personalise the greeting with the profile of this user id")
+	greeting, err := greetingFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	res, err := greetv1.NewGreetServiceClient(cc).Greet(ctx, &greetv1.GreetRequest{Greeting: greeting, UserId: *user})
+	if err != nil {
+		return err
+	}
+	return out.print(res, res.GetResult())
+}
+
+func greetServerStream(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	greeting, err := greetingFlags(flag.NewFlagSet("server-stream", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+
+	stream, err := greetv1.NewGreetServiceClient(cc).GreetManyTimes(ctx, &greetv1.GreetManyTimesRequest{Greeting: greeting})
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := out.print(res, res.GetResult()); err != nil {
+			return err
+		}
+	}
+}
+
+func greetClientStream(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	path, rest, err := inputFlags("client-stream", args)
+	if err != nil {
+		return err
+	}
+
+	stream, err := greetv1.NewGreetServiceClient(cc).LongGreet(ctx)
+	if err != nil {
+		return err
+	}
+	err = eachInput(rest, path, func(s string) error {
+		greeting, err := parseGreeting(s)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&greetv1.LongGreetRequest{Greeting: greeting})
+	})
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	// After io.EOF from Send the real error is returned by CloseAndRecv.
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return out.print(res, res.GetResult())
+}
+
+func greetBiDi(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	path, rest, err := inputFlags("bidi", args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := greetv1.NewGreetServiceClient(cc).GreetEveryone(ctx)
+	if err != nil {
+		return err
+	}
+
+	sendErr := make(chan error, 1)
+	go func() {
+		err := eachInput(rest, path, func(s string) error {
+			greeting, err := parseGreeting(s)
+			if err != nil {
+				return err
+			}
+			return stream.Send(&greetv1.GreetEveryoneRequest{Greeting: greeting})
+		})
+		if err != nil && err != io.EOF {
+			sendErr <- err
+			cancel()
+			return
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return <-sendErr
+		}
+		if err != nil {
+			select {
+			case serr := <-sendErr:
+				if serr != nil {
+					return serr
+				}
+			default:
+			}
+			return err
+		}
+		if err := out.print(res, res.GetResult()); err != nil {
+			return err
+		}
+	}
+}
+
+func greetREPL(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	if len(args) > 0 {
+		return usageError{"repl takes no arguments"}
+	}
+
+	client := greetv1.NewGreetServiceClient(cc)
+	return runREPL(ctx, replCall{
+		name: "greet",
+		open: func(ctx context.Context) (grpc.ClientStream, error) {
+			return client.GreetEveryone(ctx)
+		},
+		parse: func(line string) (proto.Message, error) {
+			greeting, err := parseGreeting(line)
+			if err != nil {
+				return nil, err
+			}
+			return &greetv1.GreetEveryoneRequest{Greeting: greeting}, nil
+		},
+		response: func() proto.Message { return &greetv1.GreetEveryoneResponse{} },
+		text: func(m proto.Message) string {
+			return m.(*greetv1.GreetEveryoneResponse).GetResult()
+		},
+	}, out)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"os"
+	"strings"
+)
+
+// printer writes responses either as text lines or as one protojson document
+// per line.
+type printer struct {
+	json bool
+	w    io.Writer
+}
+
+func (p *printer) print(msg proto.Message, text string) error {
+	line, err := p.format(msg, text)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, line)
+	return err
+}
+
+// format returns the line print would write for msg.
+func (p *printer) format(msg proto.Message, text string) (string, error) {
+	if !p.json {
+		return text, nil
+	}
+
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// eachInput calls fn with every positional argument or, when there are none,
+// with every non-empty line of path ("-" reads stdin). Lines starting with #
+// are skipped. Inputs are handed over as they are read so a stream can start
+// before stdin is closed.
+func eachInput(args []string, path string, fn func(string) error) error {
+	if len(args) > 0 {
+		for _, arg := range args {
+			if err := fn(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,246 @@
+// Command cli calls every RPC of GreetService, ProfileService and
+// CalculatorService from the shell.
+//
+//	cli [flags] greet unary --first John --last Doe
+//	cli [flags] greet profile-create --user john --locale en-GB --tz Europe/London
+//	cli [flags] greet bidi < names.txt
+//	cli [flags] calc sum 3 10
+//	cli -output json calc max --input numbers.txt
+//	cli calc repl
+//
+// Streamed inputs are taken from the positional arguments or, when there are
+// none, read line by line from --input ("-" is stdin). A failed call exits with
+// 64 plus its gRPC status code, usage errors exit with 2.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ferza17/grpc-course/calculator/calcclient"
+	"github.com/ferza17/grpc-course/greet/greetclient"
+	"github.com/ferza17/grpc-course/rpcclient"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"os"
+	"sort"
+	"strings"
+)
+
+// command is one subcommand of a service.
+type command struct {
+	name    string
+	aliases []string
+	usage   string
+	run     func(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error
+}
+
+type service struct {
+	addr       string
+	idempotent []string
+	commands   []command
+}
+
+var services = map[string]service{
+	"greet": {addr: "localhost:50051", idempotent: greetclient.Idempotent, commands: greetCommands},
+	"calc":  {addr: "localhost:50052", idempotent: calcclient.Idempotent, commands: calcCommands},
+}
+
+// usageError is returned for bad command lines, it exits with 2.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// headerFlag collects repeated -H key=value flags.
+type headerFlag []string
+
+func (h *headerFlag) String() string {
+	return strings.Join(*h, ",")
+}
+
+func (h *headerFlag) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("header %q is not key=value", v)
+	}
+	*h = append(*h, v)
+	return nil
+}
+
+var (
+	addr       = flag.String("addr", "", "server address, host:port or unix:///path, defaults to the service's local port")
+	useTLS     = flag.Bool("tls", false, "connect with TLS")
+	caFile     = flag.String("ca", "", "PEM file of the CA that signed the server certificate")
+	certFile   = flag.String("cert", "", "PEM client certificate for mutual TLS")
+	keyFile    = flag.String("key", "", "PEM client key for mutual TLS")
+	serverName = flag.String("server-name", "", "override the TLS server name")
+	skipVerify = flag.Bool("insecure-skip-verify", false, "do not verify the server certificate")
+	balancer   = flag.String("lb", "", `balancer for multi-backend targets such as file:///path: "round_robin" or "least_outstanding"`)
+	compressor = flag.String("compress", "", `compress requests with "gzip" or "zstd"`)
+	maxMsgSize = flag.Int("max-msg-size", 0, "largest message in bytes to send or receive, 0 keeps the defaults")
+	token      = flag.String("token", "", "bearer token sent as authorization metadata")
+	output     = flag.String("output", "text", "output format: text or json")
+	timeout    = flag.Duration("timeout", 0, "deadline for the whole call, 0 means none")
+	headers    headerFlag
+)
+
+func main() {
+	flag.Var(&headers, "H", "extra request metadata as key=value, repeatable")
+	flag.Usage = usage
+	flag.Parse()
+
+	err := run(flag.Args())
+	if err == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(exitCode(err))
+}
+
+func run(args []string) error {
+	if len(args) < 2 {
+		return usageError{"expected a service and a command"}
+	}
+	svc, ok := services[args[0]]
+	if !ok {
+		return usageError{fmt.Sprintf("unknown service %q", args[0])}
+	}
+	cmd, ok := lookup(svc.commands, args[1])
+	if !ok {
+		return usageError{fmt.Sprintf("unknown %s command %q", args[0], args[1])}
+	}
+	if *output != "text" && *output != "json" {
+		return usageError{fmt.Sprintf("unknown output format %q", *output)}
+	}
+
+	target := svc.addr
+	if *addr != "" {
+		target = *addr
+	}
+	creds, err := transportCredentials()
+	if err != nil {
+		return err
+	}
+	cc, err := rpcclient.Dial(context.Background(), target, rpcclient.Options{
+		Credentials:    creds,
+		Balancer:       *balancer,
+		HealthCheck:    *balancer != "",
+		Compressor:     *compressor,
+		MaxRecvMsgSize: *maxMsgSize,
+		MaxSendMsgSize: *maxMsgSize,
+	}, svc.idempotent...)
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	ctx = metadata.NewOutgoingContext(ctx, requestMetadata())
+
+	return cmd.run(ctx, cc, args[2:], &printer{json: *output == "json", w: os.Stdout})
+}
+
+func lookup(commands []command, name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+		for _, alias := range cmd.aliases {
+			if alias == name {
+				return cmd, true
+			}
+		}
+	}
+	return command{}, false
+}
+
+func transportCredentials() (credentials.TransportCredentials, error) {
+	if !*useTLS {
+		return insecure.NewCredentials(), nil
+	}
+
+	cfg := &tls.Config{ServerName: *serverName, InsecureSkipVerify: *skipVerify}
+	if *caFile != "" {
+		pem, err := os.ReadFile(*caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", *caFile)
+		}
+	}
+	if *certFile != "" {
+		cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+func requestMetadata() metadata.MD {
+	md := metadata.MD{}
+	if *token != "" {
+		md.Append("authorization", "Bearer "+*token)
+	}
+	for _, h := range headers {
+		kv := strings.SplitN(h, "=", 2)
+		md.Append(kv[0], kv[1])
+	}
+	return md
+}
+
+// exitCode follows grpcurl: 64 plus the status code of a failed call, so
+// scripts can tell NotFound from Unavailable.
+func exitCode(err error) int {
+	var uerr usageError
+	if errors.As(err, &uerr) {
+		return 2
+	}
+	if st, ok := status.FromError(err); ok {
+		return 64 + int(st.Code())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return 64 + 4
+	}
+	return 1
+}
+
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "Usage: %s [flags] <service> <command> [args]\n\nServices and commands:\n", os.Args[0])
+
+	var names []string
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, cmd := range services[name].commands {
+			aliases := ""
+			if len(cmd.aliases) > 0 {
+				aliases = " (" + strings.Join(cmd.aliases, ", ") + ")"
+			}
+			fmt.Fprintf(w, "  %s %s%s\n", name, cmd.usage, aliases)
+		}
+	}
+
+	fmt.Fprintln(w, "\nFlags:")
+	flag.PrintDefaults()
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"google.golang.org/grpc"
+	"strings"
+)
+
+// profileFlags parses the --user flag and the profile fields of a create or
+// update command. set holds the names of the fields given.
+func profileFlags(name string, args []string) (p *greetv1.Profile, set map[string]bool, err error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	user := fs.String("user", "", "user id")
+	preferredName := fs.String("name", "", "preferred name")
+	locale := fs.String("locale", "", `BCP 47 locale such as "en-GB"`)
+	pronouns := fs.String("pronouns", "", `pronouns such as "they/them"`)
+	timeZone := fs.String("tz", "", `IANA time zone such as "Asia/Jakarta"`)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, usageError{err.Error()}
+	}
+	if *user == "" {
+		return nil, nil, usageError{"--user is required"}
+	}
+	if fs.NArg() > 0 {
+		return nil, nil, usageError{name + " takes no arguments"}
+	}
+	set = map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return &greetv1.Profile{
+		UserId:        *user,
+		PreferredName: *preferredName,
+		Locale:        *locale,
+		Pronouns:      *pronouns,
+		TimeZone:      *timeZone,
+	}, set, nil
+}
+
+// userArg returns the single user id argument of name.
+func userArg(name string, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", usageError{name + " expects one user id"}
+	}
+	return args[0], nil
+}
+
+// profileText is a profile as a tab-separated line: user id, preferred name,
+// locale, pronouns and time zone.
+func profileText(p *greetv1.Profile) string {
+	return strings.Join([]string{p.GetUserId(), p.GetPreferredName(), p.GetLocale(), p.GetPronouns(), p.GetTimeZone()}, "\t")
+}
+
+func profileCreate(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	p, _, err := profileFlags("profile-create", args)
+	if err != nil {
+		return err
+	}
+
+	res, err := greetv1.NewProfileServiceClient(cc).CreateProfile(ctx, &greetv1.CreateProfileRequest{Profile: p})
+	if err != nil {
+		return err
+	}
+	return out.print(res.GetProfile(), profileText(res.GetProfile()))
+}
+
+func profileGet(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	user, err := userArg("profile-get", args)
+	if err != nil {
+		return err
+	}
+
+	res, err := greetv1.NewProfileServiceClient(cc).GetProfile(ctx, &greetv1.GetProfileRequest{UserId: user})
+	if err != nil {
+		return err
+	}
+	return out.print(res.GetProfile(), profileText(res.GetProfile()))
+}
+
+func profileUpdate(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	p, set, err := profileFlags("profile-update", args)
+	if err != nil {
+		return err
+	}
+
+	// Only the fields given are changed, --name "" clears the preferred name.
+	req := &greetv1.UpdateProfileRequest{UserId: p.GetUserId()}
+	if set["name"] {
+		req.PreferredName = &p.PreferredName
+	}
+	if set["locale"] {
+		req.Locale = &p.Locale
+	}
+	if set["pronouns"] {
+		req.Pronouns = &p.Pronouns
+	}
+	if set["tz"] {
+		req.TimeZone = &p.TimeZone
+	}
+	res, err := greetv1.NewProfileServiceClient(cc).UpdateProfile(ctx, req)
+	if err != nil {
+		return err
+	}
+	return out.print(res.GetProfile(), profileText(res.GetProfile()))
+}
+
+func profileDelete(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	user, err := userArg("profile-delete", args)
+	if err != nil {
+		return err
+	}
+
+	res, err := greetv1.NewProfileServiceClient(cc).DeleteProfile(ctx, &greetv1.DeleteProfileRequest{UserId: user})
+	if err != nil {
+		return err
+	}
+	return out.print(res, "Deleted "+user)
+}
+
+// profileList prints every profile, fetching the pages one after another.
+func profileList(ctx context.Context, cc *grpc.ClientConn, args []string, out *printer) error {
+	fs := flag.NewFlagSet("profile-list", flag.ContinueOnError)
+	pageSize := fs.Int("page-size", 0, "profiles fetched per call, 0 lets the server choose")
+	if err := fs.Parse(args); err != nil {
+		return usageError{err.Error()}
+	}
+
+	client := greetv1.NewProfileServiceClient(cc)
+	req := &greetv1.ListProfilesRequest{PageSize: int32(*pageSize)}
+	for {
+		res, err := client.ListProfiles(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, p := range res.GetProfiles() {
+			if err := out.print(p, profileText(p)); err != nil {
+				return err
+			}
+		}
+		if res.GetNextPageToken() == "" {
+			return nil
+		}
+		req.PageToken = res.GetNextPageToken()
+	}
+}
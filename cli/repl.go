@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chzyer/readline"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const replHelp = `Every line is sent as a message. Commands:
+  :close  half-close the stream (also Ctrl-D) and wait for the server to finish
+  :quit   cancel the call (also Ctrl-C)
+  :help   show this help`
+
+// replCall describes the bidi method a REPL drives.
+type replCall struct {
+	name string
+	open func(ctx context.Context) (grpc.ClientStream, error)
+	// parse turns a typed line into a request message.
+	parse func(line string) (proto.Message, error)
+	// response returns an empty response message and the text rendering of
+	// a received one.
+	response func() proto.Message
+	text     func(proto.Message) string
+}
+
+// runREPL sends every typed line on a bidi stream and prints requests and
+// responses as they happen, ending with the call status and trailers.
+func runREPL(ctx context.Context, call replCall, out *printer) error {
+	history := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		history = filepath.Join(home, ".grpc_course_history")
+	}
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          call.name + "> ",
+		HistoryFile:     history,
+		InterruptPrompt: "^C",
+		EOFPrompt:       ":close",
+	})
+	if err != nil {
+		return err
+	}
+	defer rl.Close()
+	w := rl.Stdout()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := call.open(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, replHelp)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- receive(stream, call, out, w)
+		// Unblock the prompt once the server has finished the call.
+		rl.Close()
+	}()
+
+	for {
+		line, err := rl.Readline()
+		if err == readline.ErrInterrupt {
+			cancel()
+			break
+		}
+		if err != nil {
+			// io.EOF is Ctrl-D, anything else means receive closed the prompt.
+			if err == io.EOF {
+				closeSend(stream, w)
+			}
+			break
+		}
+
+		line = strings.TrimSpace(line)
+		switch line {
+		case "":
+			continue
+		case ":help":
+			fmt.Fprintln(w, replHelp)
+			continue
+		case ":quit":
+			cancel()
+		case ":close":
+			closeSend(stream, w)
+		default:
+			msg, err := call.parse(line)
+			if err != nil {
+				fmt.Fprintln(w, "error:", err)
+				continue
+			}
+			if err := stream.SendMsg(msg); err != nil {
+				// The real error is reported by receive as the final status.
+				break
+			}
+			text, _ := out.format(msg, line)
+			fmt.Fprintf(w, "%s > %s\n", timestamp(), text)
+			continue
+		}
+		break
+	}
+
+	return <-done
+}
+
+func closeSend(stream grpc.ClientStream, w io.Writer) {
+	stream.CloseSend()
+	fmt.Fprintf(w, "%s > (half-closed)\n", timestamp())
+}
+
+// receive prints the response header, every response and the final status,
+// which it returns as the call error.
+func receive(stream grpc.ClientStream, call replCall, out *printer, w io.Writer) error {
+	if header, err := stream.Header(); err == nil && len(header) > 0 {
+		fmt.Fprintf(w, "%s header %s\n", timestamp(), formatMetadata(header))
+	}
+
+	var err error
+	for {
+		res := call.response()
+		if err = stream.RecvMsg(res); err != nil {
+			break
+		}
+		text, ferr := out.format(res, call.text(res))
+		if ferr != nil {
+			text = ferr.Error()
+		}
+		fmt.Fprintf(w, "%s < %s\n", timestamp(), text)
+	}
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	st := status.Convert(err)
+	fmt.Fprintf(w, "%s status %s", timestamp(), st.Code())
+	if st.Message() != "" {
+		fmt.Fprintf(w, ": %s", st.Message())
+	}
+	fmt.Fprintln(w)
+	if trailer := stream.Trailer(); len(trailer) > 0 {
+		fmt.Fprintf(w, "%s trailer %s\n", timestamp(), formatMetadata(trailer))
+	}
+	return err
+}
+
+func formatMetadata(md map[string][]string) string {
+	var pairs []string
+	for key, values := range md {
+		pairs = append(pairs, key+"="+strings.Join(values, ","))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func timestamp() string {
+	return time.Now().Format("15:04:05.000")
+}
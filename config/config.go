@@ -0,0 +1,305 @@
+// Package config loads the runtime settings shared by greet_server and
+// calculator_server from a JSON file.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// Config is the settings of a single server process. Fields that are absent
+// from the file keep the value of the defaults passed to Load.
+type Config struct {
+	// Addr is the address the gRPC server listens on, host:port for TCP,
+	// "unix:///path" for a Unix domain socket, see transport.Listen.
+	Addr string `json:"addr"`
+
+	// HTTPAddr is the address of the auxiliary HTTP server, empty disables it.
+	HTTPAddr string `json:"http_addr"`
+
+	// SinglePort serves the HTTP endpoints on Addr together with gRPC, see
+	// package multiplex, and ignores HTTPAddr.
+	SinglePort bool `json:"single_port"`
+
+	// ShutdownGrace is how long calls in flight may take to finish after an
+	// interrupt or SIGTERM, zero means multiplex.DefaultGrace.
+	ShutdownGrace Duration `json:"shutdown_grace"`
+
+	// Reflection registers the gRPC server reflection service. Turn it off in
+	// production so the API surface is not discoverable by anyone who can dial.
+	Reflection bool `json:"reflection"`
+
+	// Gateway serves the HTTP/JSON gateway and its OpenAPI document on HTTPAddr.
+	Gateway bool `json:"gateway"`
+
+	// GRPCWeb serves gRPC-Web and the WebSocket bridge on HTTPAddr.
+	GRPCWeb bool `json:"grpc_web"`
+
+	// WebOrigins are the cross-site origins browsers may call from, "*" allows any.
+	WebOrigins []string `json:"web_origins"`
+
+	// MaxStreamLifetime ends streams open longer than this with
+	// DeadlineExceeded, zero means no limit.
+	MaxStreamLifetime Duration `json:"max_stream_lifetime"`
+
+	// Auth verifies the credentials callers are told apart by, see package auth.
+	Auth Auth `json:"auth"`
+
+	// RateLimit is reloaded by Watch while the server runs.
+	RateLimit RateLimit `json:"rate_limit"`
+
+	// Pacing is reloaded by Watch while the server runs.
+	Pacing Pacing `json:"pacing"`
+
+	// Transport tunes the gRPC server, see package transport.
+	Transport Transport `json:"transport"`
+
+	// Idempotency stores the responses of unary calls made with an
+	// idempotency key, see package idempotency.
+	Idempotency Idempotency `json:"idempotency"`
+
+	// Cache is reloaded by Watch while the server runs. Only
+	// calculator_server caches responses.
+	Cache Cache `json:"cache"`
+
+	// Audit records every call, see package audit.
+	Audit Audit `json:"audit"`
+
+	// Profiles is where greet_server keeps user profiles.
+	Profiles Profiles `json:"profiles"`
+
+	// Backends are the servers proxy_server forwards calls to, keyed by full
+	// service name or package prefix, see proxy.NewRouter.
+	Backends map[string]string `json:"backends"`
+}
+
+// Transport is the message size and connection settings of a gRPC server.
+// Zero values keep the grpc-go defaults.
+type Transport struct {
+	// MaxRecvMsgSize and MaxSendMsgSize are in bytes. Received messages are
+	// checked after decompression.
+	MaxRecvMsgSize int `json:"max_recv_msg_size"`
+	MaxSendMsgSize int `json:"max_send_msg_size"`
+
+	// SocketMode is the permissions of Unix domain sockets, in octal such as
+	// "0660", so only the sidecar's group can connect. Empty keeps the umask.
+	SocketMode string `json:"socket_mode"`
+
+	Keepalive Keepalive `json:"keepalive"`
+}
+
+// Keepalive is the server keepalive and ping enforcement policy.
+type Keepalive struct {
+	// MaxConnectionIdle closes connections without calls for this long.
+	MaxConnectionIdle Duration `json:"max_connection_idle"`
+	// MaxConnectionAge closes connections after this long, so clients
+	// reconnect and spread over new replicas, giving calls in flight
+	// MaxConnectionAgeGrace to finish.
+	MaxConnectionAge      Duration `json:"max_connection_age"`
+	MaxConnectionAgeGrace Duration `json:"max_connection_age_grace"`
+	// Time and Timeout are how often the server pings an idle client and how
+	// long it waits for the ack.
+	Time    Duration `json:"time"`
+	Timeout Duration `json:"timeout"`
+	// MinTime is the shortest ping interval accepted from clients, pinging
+	// faster gets the connection closed. PermitWithoutStream allows pings on
+	// connections without calls.
+	MinTime             Duration `json:"min_time"`
+	PermitWithoutStream bool     `json:"permit_without_stream"`
+}
+
+// Duration is a time.Duration written as a string such as "90s" or "5m".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Auth is the settings of package auth.
+type Auth struct {
+	// APIKeys are the keys accepted in the x-api-key header, any other key
+	// is refused. Without keys the header is ignored.
+	APIKeys []string `json:"api_keys"`
+	// JWTSecret verifies bearer tokens signed with HS256, others are
+	// refused. Empty ignores the authorization header.
+	JWTSecret string `json:"jwt_secret"`
+	// Admins are the identities, as named by auth.Identity ("sub:ops"), that
+	// may manage the profiles of every user.
+	Admins []string `json:"admins"`
+	// TrustedProxies are the addresses or CIDR ranges, such as the host of
+	// proxy_server, whose x-forwarded-for header names the client. The HTTP
+	// handlers of the server itself, calling over loopback or from the
+	// address the server listens on, are always trusted.
+	TrustedProxies []string `json:"trusted_proxies"`
+}
+
+// Limit is a token bucket: Rate tokens are added per second up to Burst. A
+// zero Rate means unlimited.
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// RateLimit is the settings of package ratelimit. Every caller identity has
+// its own bucket per method.
+type RateLimit struct {
+	// Default applies to methods missing from Methods.
+	Default Limit `json:"default"`
+	// Methods is keyed by full method name, "/greet.GreatService/Greet".
+	Methods map[string]Limit `json:"methods"`
+	// MaxStreams caps the streams one identity may have open on any single
+	// method, 0 means unlimited.
+	MaxStreams int `json:"max_streams"`
+	// MethodStreams overrides MaxStreams per full method name.
+	MethodStreams map[string]int `json:"method_streams"`
+}
+
+// Pacing is the settings of package pacing, which spaces the messages of
+// server streams.
+type Pacing struct {
+	// Default applies to methods missing from Methods.
+	Default Pace `json:"default"`
+	// Methods is keyed by full method name, "/greet.v1.GreetService/GreetManyTimes".
+	// The settings of a v1 method also pace its legacy predecessor.
+	Methods map[string]Pace `json:"methods"`
+}
+
+// Pace is the pacing of one method. Mode is "none" or empty, "fixed" to wait
+// Interval between messages, or "token_bucket" to send Burst messages at
+// once and Rate per second after that. Callers may ask for a fixed interval
+// of their own, which is clamped to MinInterval and MaxInterval; a zero
+// MaxInterval ignores such requests.
+type Pace struct {
+	Mode        string   `json:"mode"`
+	Interval    Duration `json:"interval"`
+	Rate        float64  `json:"rate"`
+	Burst       int      `json:"burst"`
+	MinInterval Duration `json:"min_interval"`
+	MaxInterval Duration `json:"max_interval"`
+}
+
+// Idempotency is the settings of package idempotency.
+type Idempotency struct {
+	// TTL is how long a response is kept for duplicates of its call, zero
+	// means a day.
+	TTL Duration `json:"ttl"`
+	// Dir keeps responses in files under this directory, so they survive
+	// restarts and are shared by servers on one host. Empty keeps them in
+	// memory.
+	Dir string `json:"dir"`
+	// MaxEntries bounds the responses kept in memory, the least recently
+	// used are dropped first. Zero means 100000.
+	MaxEntries int `json:"max_entries"`
+}
+
+// Cache is the settings of package cache, which keeps the responses of
+// deterministic methods.
+type Cache struct {
+	// Methods opts methods in, keyed by full method name
+	// ("/calculator.v1.CalculatorService/Sum"), with how long their responses
+	// are kept. Zero does not cache the method.
+	Methods map[string]Duration `json:"methods"`
+	// MaxEntries and MaxBytes bound the cache, least recently used responses
+	// are evicted first. Zero means 10000 entries and 64MB.
+	MaxEntries int `json:"max_entries"`
+	MaxBytes   int `json:"max_bytes"`
+}
+
+// Audit is the settings of package audit.
+type Audit struct {
+	// Path is the file records are appended to, empty disables the audit log.
+	Path string `json:"path"`
+	// MaxSize rotates the file before it grows past this many bytes, keeping
+	// MaxBackups older files as Path.1, the newest, to Path.N. Zero means
+	// 100MB and 10 files.
+	MaxSize    int64 `json:"max_size"`
+	MaxBackups int   `json:"max_backups"`
+	// MaxMessages bounds the messages recorded per call in each direction,
+	// zero means 1000.
+	MaxMessages int `json:"max_messages"`
+	// Redact lists the fields whose values are kept out of the log, by full
+	// name ("greet.v1.Greeting.last_name"). Strings are replaced by
+	// "REDACTED", other values are cleared. Names that are not fields of a
+	// known message are refused, so a typo cannot leak the field. The same
+	// field of the legacy or v1 twin of the message is redacted as well.
+	Redact []string `json:"redact"`
+}
+
+// Profiles is the settings of the profile store, see package profile.
+type Profiles struct {
+	// Path is the bbolt file profiles are kept in, empty keeps them in
+	// memory until the server stops.
+	Path string `json:"path"`
+}
+
+// Load reads the file at path over a copy of def. An empty path returns def.
+func Load(path string, def Config) (Config, error) {
+	cfg := def
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Watch polls the file at path and calls fn with the new settings, loaded over
+// def, whenever the file changes. Invalid files are logged and skipped. Watch
+// returns when stop is closed.
+func Watch(path string, def Config, interval time.Duration, stop <-chan struct{}, fn func(Config)) {
+	if path == "" {
+		return
+	}
+
+	var last time.Time
+	if info, err := os.Stat(path); err == nil {
+		last = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(last) {
+			continue
+		}
+		last = info.ModTime()
+
+		cfg, err := Load(path, def)
+		if err != nil {
+			log.Printf("Ignoring config change: %v", err)
+			continue
+		}
+		fn(cfg)
+	}
+}
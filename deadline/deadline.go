@@ -0,0 +1,121 @@
+// Package deadline helps handlers and bridges honour cancellation: a
+// context-aware Sleep, a server interceptor capping stream lifetime, and the
+// grpc-timeout header format used to pass deadlines over HTTP.
+package deadline
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Sleep waits for d or until ctx is done, in which case it returns the
+// matching Canceled or DeadlineExceeded status error.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Err returns the status error for a done ctx, or nil while it is live.
+func Err(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// StreamInterceptor ends streams that are open longer than max with
+// DeadlineExceeded. The handler sees the limit as the deadline of its stream
+// context. A handler stuck in RecvMsg, or in SendMsg to a client that stopped
+// reading, cannot observe that, so the interceptor returns on its own when
+// the limit passes and refuses further messages from the handler. Returning
+// tears the stream down, which fails the pending call of the handler, so the
+// handler ends right after. A max of zero disables the limit.
+func StreamInterceptor(max time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if max <= 0 {
+			return handler(srv, ss)
+		}
+
+		ctx, cancel := context.WithTimeout(ss.Context(), max)
+		defer cancel()
+		ls := &lifetimeStream{ServerStream: ss, ctx: ctx}
+
+		done := make(chan error, 1)
+		go func() { done <- handler(srv, ls) }()
+
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+		}
+		if ss.Context().Err() != nil {
+			// The client went away, the handler returns as soon as its
+			// pending RecvMsg or SendMsg fails.
+			return <-done
+		}
+
+		ls.closed.Store(true)
+		return status.Errorf(codes.DeadlineExceeded, "%s exceeded the maximum stream lifetime of %s", info.FullMethod, max)
+	}
+}
+
+// lifetimeStream fails the messages of a handler past its lifetime. A send
+// blocked on flow control is not waited for: it fails once the interceptor
+// has returned and the stream is torn down.
+type lifetimeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	closed atomic.Bool
+}
+
+var errLifetime = status.Error(codes.DeadlineExceeded, "stream lifetime exceeded")
+
+func (s *lifetimeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *lifetimeStream) SendMsg(m interface{}) error {
+	if s.closed.Load() {
+		return errLifetime
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *lifetimeStream) RecvMsg(m interface{}) error {
+	if s.closed.Load() {
+		return errLifetime
+	}
+	return s.ServerStream.RecvMsg(m)
+}
+
+// ParseTimeout decodes the grpc-timeout format, such as "10S" or "250m".
+func ParseTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+
+	units := map[byte]time.Duration{
+		'H': time.Hour, 'M': time.Minute, 'S': time.Second,
+		'm': time.Millisecond, 'u': time.Microsecond, 'n': time.Nanosecond,
+	}
+	unit, ok := units[v[len(v)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
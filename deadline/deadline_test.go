@@ -0,0 +1,136 @@
+package deadline_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/deadline"
+	"github.com/ferza17/grpc-course/harness"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"io"
+	"testing"
+	"time"
+)
+
+const lifetime = 100 * time.Millisecond
+
+// serve serves handler as the bidi method /test.Stream/Call under a lifetime
+// cap, and returns a connection to it, a channel closed once the interceptor
+// returned and one closed once the handler returned.
+func serve(t *testing.T, handler grpc.StreamHandler) (cc *grpc.ClientConn, returned, ended <-chan struct{}) {
+	interceptorDone, handlerDone := make(chan struct{}), make(chan struct{})
+	measure := func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		defer close(interceptorDone)
+		return handler(srv, ss)
+	}
+	h := harness.New(t, func(s *grpc.Server) {
+		s.RegisterService(&grpc.ServiceDesc{
+			ServiceName: "test.Stream",
+			HandlerType: (*interface{})(nil),
+			Streams: []grpc.StreamDesc{{
+				StreamName: "Call",
+				Handler: func(srv interface{}, ss grpc.ServerStream) error {
+					defer close(handlerDone)
+					return handler(srv, ss)
+				},
+				ServerStreams: true,
+				ClientStreams: true,
+			}},
+		}, struct{}{})
+	}, grpc.ChainStreamInterceptor(measure, deadline.StreamInterceptor(lifetime)))
+	return h.Conn, interceptorDone, handlerDone
+}
+
+// finish reads the stream to its end and returns its status.
+func finish(stream grpc.ClientStream) error {
+	for {
+		if err := stream.RecvMsg(new(wrapperspb.BytesValue)); err != nil {
+			return err
+		}
+	}
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	big := &wrapperspb.BytesValue{Value: make([]byte, 64<<10)}
+	for _, tt := range []struct {
+		name    string
+		handler grpc.StreamHandler
+	}{
+		{
+			name: "handler ignoring the deadline",
+			handler: func(_ interface{}, ss grpc.ServerStream) error {
+				// Nothing is sent, so RecvMsg blocks.
+				for {
+					if err := ss.RecvMsg(new(emptypb.Empty)); err != nil {
+						return err
+					}
+				}
+			},
+		},
+		{
+			name: "client not reading",
+			handler: func(_ interface{}, ss grpc.ServerStream) error {
+				for {
+					if err := ss.SendMsg(big); err != nil {
+						return err
+					}
+				}
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cc, returned, ended := serve(t, tt.handler)
+			stream, err := cc.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}, "/test.Stream/Call")
+			if err != nil {
+				t.Fatalf("NewStream: %v", err)
+			}
+			if err := stream.SendMsg(&emptypb.Empty{}); err != nil && err != io.EOF {
+				t.Fatalf("SendMsg: %v", err)
+			}
+
+			// The client reads nothing until the cap has ended the stream.
+			select {
+			case <-returned:
+			case <-time.After(20 * lifetime):
+				t.Fatal("stream still open long after its lifetime")
+			}
+			if err := finish(stream); status.Code(err) != codes.DeadlineExceeded {
+				t.Errorf("status = %v, want DeadlineExceeded", err)
+			}
+			select {
+			case <-ended:
+			case <-time.After(5 * time.Second):
+				t.Error("handler still running after the stream ended")
+			}
+		})
+	}
+}
+
+func TestStreamInterceptorHandlerDone(t *testing.T) {
+	cc, _, _ := serve(t, func(interface{}, grpc.ServerStream) error {
+		return status.Error(codes.Aborted, "done")
+	})
+	stream, err := cc.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/test.Stream/Call")
+	if err != nil {
+		t.Fatalf("NewStream: %v", err)
+	}
+	stream.CloseSend()
+	if err := finish(stream); status.Code(err) != codes.Aborted {
+		t.Errorf("status = %v, want the handler's Aborted", err)
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	for v, want := range map[string]time.Duration{"10S": 10 * time.Second, "250m": 250 * time.Millisecond, "1H": time.Hour} {
+		if got, ok := deadline.ParseTimeout(v); !ok || got != want {
+			t.Errorf("ParseTimeout(%q) = %v, %v; want %v", v, got, ok, want)
+		}
+	}
+	for _, v := range []string{"", "S", "-1S", "10x", "1.5S"} {
+		if _, ok := deadline.ParseTimeout(v); ok {
+			t.Errorf("ParseTimeout(%q) succeeded", v)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package descriptor serves the compiled FileDescriptorSet of the services
+// registered on a gRPC server, so generic tools can work without the .proto files.
+package descriptor
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ServiceInfoProvider is implemented by *grpc.Server.
+type ServiceInfoProvider interface {
+	GetServiceInfo() map[string]grpc.ServiceInfo
+}
+
+// Set returns the FileDescriptorSet holding the files that declare the services
+// of s, together with their transitive imports, dependencies first. The grpc.*
+// infrastructure services such as reflection are left out.
+func Set(s ServiceInfoProvider) (*descriptorpb.FileDescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+
+	var names []string
+	for name := range s.GetServiceInfo() {
+		if strings.HasPrefix(name, "grpc.") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, err
+		}
+		add(d.ParentFile())
+	}
+
+	return set, nil
+}
+
+// Handler serves Set(s). The response is binary protobuf unless the request
+// asks for ?format=json.
+func Handler(s ServiceInfoProvider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set, err := Set(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var data []byte
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			data, err = protojson.Marshal(set)
+		} else {
+			w.Header().Set("Content-Type", "application/x-protobuf")
+			data, err = proto.Marshal(set)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Write(data)
+	})
+}
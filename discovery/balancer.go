@@ -0,0 +1,67 @@
+package discovery
+
+import (
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"sync"
+	"sync/atomic"
+)
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(LeastOutstanding, &leastOutstandingBuilder{}, base.Config{HealthCheck: true}))
+}
+
+type leastOutstandingBuilder struct{}
+
+// Build is called whenever the set of ready backends changes. The counters
+// start again from zero then, which only skews picks for calls in flight
+// across the change.
+func (*leastOutstandingBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	p := &leastOutstandingPicker{}
+	for sc := range info.ReadySCs {
+		p.backends = append(p.backends, &backend{sc: sc})
+	}
+	return p
+}
+
+type backend struct {
+	sc          balancer.SubConn
+	outstanding int64
+}
+
+// leastOutstandingPicker sends each call to the backend with the fewest calls
+// in flight. The scan starts one past the previous pick so idle backends
+// share the load evenly.
+type leastOutstandingPicker struct {
+	backends []*backend
+
+	mu   sync.Mutex
+	next int
+}
+
+func (p *leastOutstandingPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	p.mu.Lock()
+	start := p.next
+	p.next = (p.next + 1) % len(p.backends)
+	p.mu.Unlock()
+
+	var best *backend
+	for i := range p.backends {
+		b := p.backends[(start+i)%len(p.backends)]
+		if best == nil || atomic.LoadInt64(&b.outstanding) < atomic.LoadInt64(&best.outstanding) {
+			best = b
+		}
+	}
+
+	atomic.AddInt64(&best.outstanding, 1)
+	return balancer.PickResult{
+		SubConn: best.sc,
+		Done: func(balancer.DoneInfo) {
+			atomic.AddInt64(&best.outstanding, -1)
+		},
+	}, nil
+}
@@ -0,0 +1,48 @@
+// Package discovery lets clients spread calls over several server replicas.
+//
+// Importing it registers two resolvers:
+//
+//	file:///etc/grpc/calculator.txt  backends listed one "host:port" per line
+//	file:///etc/grpc/calculator.d/   every file of the directory, re-read as files come and go
+//	srv:///_grpc._tcp.calculator.example.com  DNS SRV records
+//
+// and the LeastOutstanding balancer. ServiceConfig selects a balancer and turns
+// on health checks, which take a backend out of rotation while its
+// grpc.health.v1 service reports anything but SERVING.
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Balancer names accepted by ServiceConfig.
+const (
+	RoundRobin       = "round_robin"
+	LeastOutstanding = "least_outstanding"
+)
+
+// ServiceConfig returns the default service config JSON for a channel using
+// the given balancer, with client-side health checking when healthCheck is set.
+// An empty balancer keeps the grpc-go default pick_first, which ignores health
+// checks, so health checking without a balancer uses RoundRobin.
+func ServiceConfig(balancer string, healthCheck bool) (string, error) {
+	if balancer == "" && healthCheck {
+		balancer = RoundRobin
+	}
+	cfg := map[string]interface{}{}
+	switch balancer {
+	case "":
+	case RoundRobin, LeastOutstanding:
+		cfg["loadBalancingConfig"] = []map[string]interface{}{{balancer: map[string]interface{}{}}}
+	default:
+		return "", fmt.Errorf("unknown balancer %q", balancer)
+	}
+	if healthCheck {
+		// An empty service name asks for the health of the whole server.
+		cfg["healthCheckConfig"] = map[string]string{"serviceName": ""}
+	}
+
+	data, err := json.Marshal(cfg)
+	return string(data), err
+}
@@ -0,0 +1,231 @@
+package discovery_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/discovery"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/rpcclient"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/resolver"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clientConn records the states a resolver pushes.
+type clientConn struct {
+	resolver.ClientConn
+	states chan resolver.State
+}
+
+func (c *clientConn) UpdateState(s resolver.State) error {
+	c.states <- s
+	return nil
+}
+
+func (c *clientConn) ReportError(error) {}
+
+// resolve builds the resolver of target and returns it with its first
+// addresses.
+func resolve(t *testing.T, target string) (resolver.Resolver, *clientConn, []string) {
+	t.Helper()
+	u, err := url.Parse(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc := &clientConn{states: make(chan resolver.State, 10)}
+	r, err := resolver.Get(u.Scheme).Build(resolver.Target{URL: *u}, cc, resolver.BuildOptions{})
+	if err != nil {
+		t.Fatalf("Build(%s): %v", target, err)
+	}
+	t.Cleanup(r.Close)
+	return r, cc, next(t, cc)
+}
+
+// next returns the addresses of the next state pushed to cc.
+func next(t *testing.T, cc *clientConn) []string {
+	t.Helper()
+	select {
+	case s := <-cc.states:
+		var addrs []string
+		for _, a := range s.Addresses {
+			addrs = append(addrs, a.Addr)
+		}
+		return addrs
+	case <-time.After(5 * time.Second):
+		t.Fatal("no state pushed")
+		return nil
+	}
+}
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileResolver(t *testing.T) {
+	dir := t.TempDir()
+	write(t, filepath.Join(dir, "a"), "10.0.0.2:50051\n# drained: 10.0.0.9:50051\n\n10.0.0.1:50051\n")
+	write(t, filepath.Join(dir, "b"), "10.0.0.1:50051\n")
+	write(t, filepath.Join(dir, ".hidden"), "10.0.0.8:50051\n")
+
+	r, cc, got := resolve(t, "file://"+dir)
+	if want := []string{"10.0.0.1:50051", "10.0.0.2:50051"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("addresses = %q, want %q", got, want)
+	}
+
+	write(t, filepath.Join(dir, "c"), "10.0.0.3:50051\n")
+	r.ResolveNow(resolver.ResolveNowOptions{})
+	if got, want := next(t, cc), []string{"10.0.0.1:50051", "10.0.0.2:50051", "10.0.0.3:50051"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("addresses after adding a file = %q, want %q", got, want)
+	}
+}
+
+func TestFileResolverMissingFile(t *testing.T) {
+	u, _ := url.Parse("file://" + filepath.Join(t.TempDir(), "missing.txt"))
+	cc := &clientConn{states: make(chan resolver.State, 1)}
+	if _, err := resolver.Get("file").Build(resolver.Target{URL: *u}, cc, resolver.BuildOptions{}); err == nil {
+		t.Error("Build of a missing file succeeded")
+	}
+}
+
+func TestSRVResolver(t *testing.T) {
+	lookup := discovery.LookupSRV
+	t.Cleanup(func() { discovery.LookupSRV = lookup })
+	var name string
+	discovery.LookupSRV = func(_ context.Context, _, _, n string) (string, []*net.SRV, error) {
+		name = n
+		return n, []*net.SRV{
+			{Target: "b.example.com.", Port: 50051},
+			{Target: "a.example.com.", Port: 50052},
+		}, nil
+	}
+
+	_, _, got := resolve(t, "srv:///_grpc._tcp.calculator.example.com")
+	if name != "_grpc._tcp.calculator.example.com" {
+		t.Errorf("looked up %q, want _grpc._tcp.calculator.example.com", name)
+	}
+	if want := []string{"a.example.com:50052", "b.example.com:50051"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("addresses = %q, want %q", got, want)
+	}
+}
+
+func TestServiceConfig(t *testing.T) {
+	for _, tt := range []struct {
+		balancer    string
+		healthCheck bool
+		want        string
+	}{
+		{discovery.LeastOutstanding, false, `{"loadBalancingConfig":[{"least_outstanding":{}}]}`},
+		{discovery.RoundRobin, true, `{"healthCheckConfig":{"serviceName":""},"loadBalancingConfig":[{"round_robin":{}}]}`},
+		{"", true, `{"healthCheckConfig":{"serviceName":""},"loadBalancingConfig":[{"round_robin":{}}]}`},
+		{"", false, `{}`},
+	} {
+		got, err := discovery.ServiceConfig(tt.balancer, tt.healthCheck)
+		if err != nil || got != tt.want {
+			t.Errorf("ServiceConfig(%q, %v) = %s, %v; want %s", tt.balancer, tt.healthCheck, got, err, tt.want)
+		}
+	}
+	if _, err := discovery.ServiceConfig("random", false); err == nil {
+		t.Error("ServiceConfig accepted an unknown balancer")
+	}
+}
+
+// backends starts n GreetService servers and returns a file target listing
+// them and their health servers.
+func backends(t *testing.T, n int) (string, []*health.Server) {
+	var addrs []string
+	var hs []*health.Server
+	for i := 0; i < n; i++ {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := grpc.NewServer()
+		h := health.NewServer()
+		greetservice.Register(s, &greetservice.Server{})
+		healthpb.RegisterHealthServer(s, h)
+		go s.Serve(lis)
+		t.Cleanup(s.Stop)
+		addrs = append(addrs, lis.Addr().String())
+		hs = append(hs, h)
+	}
+	path := filepath.Join(t.TempDir(), "backends.txt")
+	write(t, path, strings.Join(addrs, "\n"))
+	return "file://" + path, hs
+}
+
+// peers makes n Greet calls on cc and returns the backends that answered.
+func peers(t *testing.T, cc *grpc.ClientConn, n int) map[string]bool {
+	t.Helper()
+	got := map[string]bool{}
+	for i := 0; i < n; i++ {
+		var p peer.Peer
+		if _, err := greetv1.NewGreetServiceClient(cc).Greet(context.Background(), &greetv1.GreetRequest{}, grpc.Peer(&p), grpc.WaitForReady(true)); err != nil {
+			t.Fatalf("Greet: %v", err)
+		}
+		got[p.Addr.String()] = true
+	}
+	return got
+}
+
+func TestLeastOutstanding(t *testing.T) {
+	target, _ := backends(t, 2)
+	serviceConfig, err := discovery.ServiceConfig(discovery.LeastOutstanding, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer cc.Close()
+	// Both backends have to be ready for the picker to choose between them.
+	for len(peers(t, cc, 4)) < 2 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A stream stays outstanding on its backend until it ends.
+	stream, err := greetv1.NewGreetServiceClient(cc).LongGreet(context.Background())
+	if err != nil {
+		t.Fatalf("LongGreet: %v", err)
+	}
+	if got := peers(t, cc, 10); len(got) != 1 {
+		t.Errorf("calls during the stream went to %v, want the idle backend only", got)
+	}
+	if _, err := stream.CloseAndRecv(); err != nil {
+		t.Fatalf("LongGreet: %v", err)
+	}
+	if got := peers(t, cc, 10); len(got) != 2 {
+		t.Errorf("calls after the stream went to %v, want both backends", got)
+	}
+}
+
+func TestHealthCheckWithoutBalancer(t *testing.T) {
+	target, hs := backends(t, 2)
+	hs[0].SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	cc, err := rpcclient.Dial(context.Background(), target, rpcclient.Options{HealthCheck: true})
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer cc.Close()
+	if got := peers(t, cc, 10); len(got) != 1 {
+		t.Errorf("calls went to %v, want the serving backend only", got)
+	}
+}
@@ -0,0 +1,148 @@
+package discovery
+
+import (
+	"bufio"
+	"google.golang.org/grpc/resolver"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FilePollInterval is how often file targets are re-read. Polling is used
+// rather than file system events because mounted config maps are swapped by
+// renaming symlinks, which event based watchers tend to miss.
+var FilePollInterval = 5 * time.Second
+
+func init() {
+	resolver.Register(&fileBuilder{})
+}
+
+type fileBuilder struct{}
+
+func (*fileBuilder) Scheme() string {
+	return "file"
+}
+
+func (*fileBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	r := &fileResolver{
+		path: target.URL.Path,
+		cc:   cc,
+		now:  make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	if err := r.update(); err != nil {
+		return nil, err
+	}
+	go r.watch()
+	return r, nil
+}
+
+type fileResolver struct {
+	path string
+	cc   resolver.ClientConn
+	now  chan struct{}
+	done chan struct{}
+	once sync.Once
+
+	last string
+}
+
+func (r *fileResolver) watch() {
+	ticker := time.NewTicker(FilePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.now:
+		}
+		if err := r.update(); err != nil {
+			r.cc.ReportError(err)
+		}
+	}
+}
+
+// update pushes the backend list to the channel when it changed.
+func (r *fileResolver) update() error {
+	addrs, err := readAddresses(r.path)
+	if err != nil {
+		return err
+	}
+
+	key := strings.Join(addrs, ",")
+	if key == r.last {
+		return nil
+	}
+	r.last = key
+
+	state := resolver.State{}
+	for _, addr := range addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+	}
+	return r.cc.UpdateState(state)
+}
+
+func (r *fileResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+func (r *fileResolver) Close() {
+	r.once.Do(func() { close(r.done) })
+}
+
+// readAddresses reads path, or every regular file in it when it is a
+// directory, and returns the sorted unique addresses.
+func readAddresses(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, e := range entries {
+			// Skip hidden files such as the ..data links of config maps.
+			if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	var addrs []string
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || seen[line] {
+				continue
+			}
+			seen[line] = true
+			addrs = append(addrs, line)
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(addrs)
+	return addrs, nil
+}
@@ -0,0 +1,100 @@
+package discovery
+
+import (
+	"context"
+	"google.golang.org/grpc/resolver"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SRVRefreshInterval is how often SRV records are looked up again.
+var SRVRefreshInterval = 30 * time.Second
+
+// LookupSRV looks the SRV records up, net.DefaultResolver unless replaced,
+// for example by a resolver asking another DNS server.
+var LookupSRV = net.DefaultResolver.LookupSRV
+
+func init() {
+	resolver.Register(&srvBuilder{})
+}
+
+type srvBuilder struct{}
+
+func (*srvBuilder) Scheme() string {
+	return "srv"
+}
+
+func (*srvBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	r := &srvResolver{
+		name: strings.TrimPrefix(target.URL.Path, "/"),
+		cc:   cc,
+		now:  make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go r.watch()
+	return r, nil
+}
+
+type srvResolver struct {
+	name string
+	cc   resolver.ClientConn
+	now  chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func (r *srvResolver) watch() {
+	ticker := time.NewTicker(SRVRefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.update(); err != nil {
+			r.cc.ReportError(err)
+		}
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.now:
+		}
+	}
+}
+
+func (r *srvResolver) update() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Empty service and proto look the name up as given, e.g.
+	// _grpc._tcp.calculator.example.com.
+	_, records, err := LookupSRV(ctx, "", "", r.name)
+	if err != nil {
+		return err
+	}
+
+	var addrs []string
+	for _, rec := range records {
+		host := strings.TrimSuffix(rec.Target, ".")
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(int(rec.Port))))
+	}
+	sort.Strings(addrs)
+
+	state := resolver.State{}
+	for _, addr := range addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+	}
+	return r.cc.UpdateState(state)
+}
+
+func (r *srvResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+func (r *srvResolver) Close() {
+	r.once.Do(func() { close(r.done) })
+}
@@ -0,0 +1,280 @@
+// Package fake is the scripting and recording core of greetfake and calcfake,
+// in-process fakes of GreatService and SumService for tests of client code.
+//
+// Every RPC method is a Method. A test queues one Script per expected call;
+// a script is a list of Steps, each optionally delayed, that either send a
+// response or end the call with an error:
+//
+//	f.GreetManyTimes.Enqueue(
+//		fake.Send(&greetpb.GreetManyTimesResponse{Result: "a"}),
+//		fake.SendAfter(50*time.Millisecond, &greetpb.GreetManyTimesResponse{Result: "b"}),
+//		fake.Fail(status.Error(codes.Unavailable, "backend went away")),
+//	)
+//
+// Scripts are used in order and the last one is repeated. A call without any
+// script fails with Unimplemented. Every call is recorded with its metadata
+// and requests.
+package fake
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"sync"
+	"time"
+)
+
+// Step is one scripted event of a call.
+type Step struct {
+	// Delay is waited before the step.
+	Delay time.Duration
+	// Message is sent to the client, unless Err is set.
+	Message proto.Message
+	// Err ends the call with this error, normally a status error.
+	Err error
+}
+
+// Send sends m.
+func Send(m proto.Message) Step {
+	return Step{Message: m}
+}
+
+// SendAfter waits d, then sends m.
+func SendAfter(d time.Duration, m proto.Message) Step {
+	return Step{Delay: d, Message: m}
+}
+
+// Fail ends the call with err.
+func Fail(err error) Step {
+	return Step{Err: err}
+}
+
+// FailAfter waits d, then ends the call with err.
+func FailAfter(d time.Duration, err error) Step {
+	return Step{Delay: d, Err: err}
+}
+
+// Script is the steps played for one call.
+type Script []Step
+
+// Call is the record of one call.
+type Call struct {
+	Metadata metadata.MD
+	Requests []proto.Message
+}
+
+// Method scripts and records one RPC method. It is safe for concurrent use.
+type Method struct {
+	name string
+
+	mu      sync.Mutex
+	scripts []Script
+	calls   []*Call
+}
+
+// NewMethod returns a Method for the full method name, used in errors.
+func NewMethod(name string) *Method {
+	return &Method{name: name}
+}
+
+// Enqueue adds the script of the next call.
+func (m *Method) Enqueue(steps ...Step) *Method {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scripts = append(m.scripts, steps)
+	return m
+}
+
+// Calls returns copies of the records of all calls so far.
+func (m *Method) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	calls := make([]Call, len(m.calls))
+	for i, c := range m.calls {
+		calls[i] = Call{Metadata: c.Metadata.Copy(), Requests: append([]proto.Message(nil), c.Requests...)}
+	}
+	return calls
+}
+
+// Reset drops scripts and records.
+func (m *Method) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scripts = nil
+	m.calls = nil
+}
+
+// Requests returns every request received by m across all calls, as T.
+func Requests[T proto.Message](m *Method) []T {
+	var reqs []T
+	for _, c := range m.Calls() {
+		for _, req := range c.Requests {
+			reqs = append(reqs, req.(T))
+		}
+	}
+	return reqs
+}
+
+// begin records a new call and returns its script.
+func (m *Method) begin(ctx context.Context) (*Call, Script, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	call := &Call{Metadata: md.Copy()}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, call)
+
+	switch len(m.scripts) {
+	case 0:
+		return call, nil, status.Errorf(codes.Unimplemented, "fake: no script for %s", m.name)
+	case 1:
+		return call, m.scripts[0], nil
+	}
+	script := m.scripts[0]
+	m.scripts = m.scripts[1:]
+	return call, script, nil
+}
+
+func (m *Method) record(call *Call, req proto.Message) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	call.Requests = append(call.Requests, proto.Clone(req))
+}
+
+// play runs the steps, handing messages to send. It stops at the first Err,
+// send error or cancellation of ctx.
+func play(ctx context.Context, script Script, send func(proto.Message) error) error {
+	for _, step := range script {
+		if step.Delay > 0 {
+			timer := time.NewTimer(step.Delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return status.FromContextError(ctx.Err()).Err()
+			case <-timer.C:
+			}
+		}
+		if step.Err != nil {
+			return step.Err
+		}
+		if step.Message != nil {
+			if err := send(step.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Unary plays the script of a unary call: the first message is the response.
+func Unary(ctx context.Context, m *Method, req proto.Message) (proto.Message, error) {
+	call, script, err := m.begin(ctx)
+	m.record(call, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var res proto.Message
+	err = play(ctx, script, func(msg proto.Message) error {
+		if res == nil {
+			res = msg
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, status.Errorf(codes.Internal, "fake: script for %s sends no response", m.name)
+	}
+	return res, nil
+}
+
+// ServerStream plays the script of a server-streaming call.
+func ServerStream(ctx context.Context, m *Method, req proto.Message, send func(proto.Message) error) error {
+	call, script, err := m.begin(ctx)
+	m.record(call, req)
+	if err != nil {
+		return err
+	}
+	return play(ctx, script, send)
+}
+
+// ClientStream records requests until the client half-closes, then plays the
+// script like Unary. recv returns io.EOF at the end of the stream.
+func ClientStream(ctx context.Context, m *Method, recv func() (proto.Message, error)) (proto.Message, error) {
+	call, script, err := m.begin(ctx)
+	for {
+		req, rerr := recv()
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return nil, rerr
+		}
+		m.record(call, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res proto.Message
+	err = play(ctx, script, func(msg proto.Message) error {
+		if res == nil {
+			res = msg
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, status.Errorf(codes.Internal, "fake: script for %s sends no response", m.name)
+	}
+	return res, nil
+}
+
+// BiDi records requests as they arrive while playing the script
+// independently of them. When the script ends without an error the call
+// finishes once the client half-closes.
+func BiDi(ctx context.Context, m *Method, recv func() (proto.Message, error), send func(proto.Message) error) error {
+	call, script, err := m.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			m.record(call, req)
+		}
+	}()
+
+	if err := play(ctx, script, send); err != nil {
+		return err
+	}
+	if err := <-recvErr; err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// As returns the scripted message m as the response type T of its method. A
+// script of the wrong type fails the call with Internal rather than panicking
+// in the server.
+func As[T proto.Message](m proto.Message) (T, error) {
+	res, ok := m.(T)
+	if !ok {
+		return res, status.Errorf(codes.Internal, "fake: scripted %T, want %T", m, res)
+	}
+	return res, nil
+}
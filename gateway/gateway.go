@@ -0,0 +1,290 @@
+// Package gateway exposes gRPC services over HTTP/JSON. Every unary and
+// server-streaming method is served as POST /<package.Service>/<Method> with a
+// protojson request body. Unary methods answer with a single JSON document,
+// server-streaming methods with newline-delimited JSON, or with Server-Sent
+// Events when the request accepts text/event-stream. A Grpc-Timeout header
+// sets the call deadline. Calls carry the address of the HTTP client in
+// x-forwarded-for, see package auth.
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/deadline"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MetadataHeaderPrefix marks HTTP headers that are forwarded as gRPC metadata,
+// and gRPC response headers written back to the HTTP response.
+const MetadataHeaderPrefix = "Grpc-Metadata-"
+
+// DefaultMaxBodySize is the grpc-go default of the largest message a server
+// receives.
+const DefaultMaxBodySize = 4 << 20
+
+var marshaler = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// Gateway is an http.Handler that forwards requests to a gRPC connection.
+type Gateway struct {
+	// MaxBodySize bounds request bodies in bytes, zero means
+	// DefaultMaxBodySize. Servers set it to their largest received message.
+	MaxBodySize int64
+
+	cc      grpc.ClientConnInterface
+	methods map[string]protoreflect.MethodDescriptor
+}
+
+// New returns a Gateway serving the unary and server-streaming methods of
+// services by calling them on cc. Client-streaming and bidi methods are skipped.
+func New(cc grpc.ClientConnInterface, services ...protoreflect.ServiceDescriptor) *Gateway {
+	g := &Gateway{cc: cc, methods: map[string]protoreflect.MethodDescriptor{}}
+	for _, sd := range services {
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			if md.IsStreamingClient() {
+				continue
+			}
+			g.methods[methodPath(md)] = md
+		}
+	}
+	return g
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	md, ok := g.methods[r.URL.Path]
+	if !ok {
+		writeError(w, status.Errorf(codes.Unimplemented, "unknown method %s", r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	in := newMessage(md.Input())
+	maxBodySize := g.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeStatus(w, http.StatusRequestEntityTooLarge, status.Newf(codes.ResourceExhausted, "request body larger than %d bytes", tooLarge.Limit))
+		return
+	}
+	if err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "read body: %v", err))
+		return
+	}
+	if len(body) > 0 {
+		if err := protojson.Unmarshal(body, in); err != nil {
+			writeError(w, status.Errorf(codes.InvalidArgument, "decode %s: %v", md.Input().FullName(), err))
+			return
+		}
+	}
+
+	ctx := metadata.NewOutgoingContext(r.Context(), outgoingMetadata(r))
+	if timeout, ok := deadline.ParseTimeout(r.Header.Get("Grpc-Timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if md.IsStreamingServer() {
+		g.serverStream(ctx, w, r, md, in)
+		return
+	}
+	g.unary(ctx, w, md, in)
+}
+
+func (g *Gateway) unary(ctx context.Context, w http.ResponseWriter, md protoreflect.MethodDescriptor, in proto.Message) {
+	var header metadata.MD
+	out := newMessage(md.Output())
+	if err := g.cc.Invoke(ctx, methodPath(md), in, out, grpc.Header(&header)); err != nil {
+		writeHeader(w, header)
+		writeError(w, err)
+		return
+	}
+
+	data, err := marshaler.Marshal(out)
+	if err != nil {
+		writeError(w, status.Errorf(codes.Internal, "encode response: %v", err))
+		return
+	}
+
+	writeHeader(w, header)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (g *Gateway) serverStream(ctx context.Context, w http.ResponseWriter, r *http.Request, md protoreflect.MethodDescriptor, in proto.Message) {
+	desc := &grpc.StreamDesc{StreamName: string(md.Name()), ServerStreams: true}
+	stream, err := g.cc.NewStream(ctx, desc, methodPath(md))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := stream.SendMsg(in); err != nil && err != io.EOF {
+		writeError(w, err)
+		return
+	}
+	if err := stream.CloseSend(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The response status is committed with the first message, so the
+	// header metadata has to be known before anything is written.
+	header, err := stream.Header()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeHeader(w, header)
+
+	sse := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	flusher, _ := w.(http.Flusher)
+
+	for {
+		out := newMessage(md.Output())
+		err := stream.RecvMsg(out)
+		if err == io.EOF {
+			return
+		}
+
+		if err != nil {
+			// Headers are already on the wire, the failure travels in-band.
+			data, _ := marshaler.Marshal(status.Convert(err).Proto())
+			if sse {
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			} else {
+				fmt.Fprintf(w, "{\"error\":%s}\n", data)
+			}
+			return
+		}
+
+		data, err := marshaler.Marshal(out)
+		if err != nil {
+			return
+		}
+		if sse {
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		} else {
+			fmt.Fprintf(w, "{\"result\":%s}\n", data)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+func methodPath(md protoreflect.MethodDescriptor) string {
+	return "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+}
+
+// newMessage prefers the generated Go type so handlers on the other side of an
+// in-process connection see the concrete message.
+func newMessage(md protoreflect.MessageDescriptor) proto.Message {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName()); err == nil {
+		return mt.New().Interface()
+	}
+	return dynamicpb.NewMessage(md)
+}
+
+func outgoingMetadata(r *http.Request) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		switch {
+		case key == "Authorization":
+			md.Append("authorization", values...)
+		case key == "Idempotency-Key":
+			// The standard HTTP header, as well as the prefixed one, makes
+			// retries safe, see package idempotency.
+			md.Append("idempotency-key", values...)
+		case key == "Cache-Control":
+			md.Append("cache-control", values...)
+		case strings.HasPrefix(key, MetadataHeaderPrefix):
+			md.Append(strings.TrimPrefix(key, MetadataHeaderPrefix), values...)
+		}
+	}
+	auth.SetForwardedFor(md, r)
+	return md
+}
+
+func writeHeader(w http.ResponseWriter, md metadata.MD) {
+	for key, values := range md {
+		if key == "content-type" {
+			continue
+		}
+		if key == "retry-after" {
+			// Rate limited calls also get the standard HTTP header.
+			w.Header()["Retry-After"] = values
+		}
+		for _, v := range values {
+			w.Header().Add(MetadataHeaderPrefix+key, v)
+		}
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeStatus(w, HTTPStatus(st.Code()), st)
+}
+
+func writeStatus(w http.ResponseWriter, code int, st *status.Status) {
+	data, _ := marshaler.Marshal(st.Proto())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// HTTPStatus maps a gRPC status code to the closest HTTP status, following
+// the table in google/rpc/code.proto.
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,30 @@
+package gateway_test
+
+import (
+	"github.com/ferza17/grpc-course/gateway"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	gw := gateway.New(harness.Greet(t).Conn, greetv1.File_greet_v1_greet_proto.Services().ByName("GreetService"))
+	gw.MaxBodySize = 64
+
+	for _, tt := range []struct {
+		body string
+		want int
+	}{
+		{`{"greeting": {"firstName": "Ann"}}`, http.StatusOK},
+		{`{"greeting": {"firstName": "` + strings.Repeat("a", 64) + `"}}`, http.StatusRequestEntityTooLarge},
+	} {
+		w := httptest.NewRecorder()
+		gw.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/greet.v1.GreetService/Greet", strings.NewReader(tt.body)))
+		if w.Code != tt.want {
+			t.Errorf("POST of %d bytes = %d %s, want %d", len(tt.body), w.Code, w.Body, tt.want)
+		}
+	}
+}
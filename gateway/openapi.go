@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"encoding/json"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"net/http"
+)
+
+const statusSchema = "google.rpc.Status"
+
+// OpenAPI builds an OpenAPI 3 document for the routes New serves for services,
+// derived from their proto descriptors.
+func OpenAPI(title string, services ...protoreflect.ServiceDescriptor) map[string]interface{} {
+	paths := map[string]interface{}{}
+	schemas := map[string]interface{}{
+		statusSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":    map[string]interface{}{"type": "integer", "format": "int32"},
+				"message": map[string]interface{}{"type": "string"},
+				"details": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+			},
+		},
+	}
+
+	for _, sd := range services {
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			if md.IsStreamingClient() {
+				continue
+			}
+			addSchema(schemas, md.Input())
+			addSchema(schemas, md.Output())
+
+			out := ref(md.Output())
+			content := map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": out},
+			}
+			if md.IsStreamingServer() {
+				content = map[string]interface{}{
+					"application/x-ndjson": map[string]interface{}{"schema": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"result": out,
+							"error":  map[string]interface{}{"$ref": "#/components/schemas/" + statusSchema},
+						},
+					}},
+					"text/event-stream": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+				}
+			}
+
+			paths[methodPath(md)] = map[string]interface{}{
+				"post": map[string]interface{}{
+					"operationId": string(sd.Name()) + "_" + string(md.Name()),
+					"tags":        []string{string(sd.FullName())},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{"schema": ref(md.Input())},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{"description": "OK", "content": content},
+						"default": map[string]interface{}{
+							"description": "gRPC status of a failed call",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{"$ref": "#/components/schemas/" + statusSchema},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi":    "3.0.3",
+		"info":       map[string]interface{}{"title": title, "version": "1.0"},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+// OpenAPIHandler serves OpenAPI(title, services...) as JSON.
+func OpenAPIHandler(title string, services ...protoreflect.ServiceDescriptor) http.Handler {
+	doc := OpenAPI(title, services...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(doc)
+	})
+}
+
+func ref(md protoreflect.MessageDescriptor) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + string(md.FullName())}
+}
+
+func addSchema(schemas map[string]interface{}, md protoreflect.MessageDescriptor) {
+	name := string(md.FullName())
+	if _, ok := schemas[name]; ok {
+		return
+	}
+
+	properties := map[string]interface{}{}
+	schemas[name] = map[string]interface{}{"type": "object", "properties": properties}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		properties[fd.JSONName()] = fieldSchema(schemas, fd)
+	}
+}
+
+func fieldSchema(schemas map[string]interface{}, fd protoreflect.FieldDescriptor) map[string]interface{} {
+	if fd.IsMap() {
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": kindSchema(schemas, fd.MapValue()),
+		}
+	}
+	if fd.IsList() {
+		return map[string]interface{}{"type": "array", "items": kindSchema(schemas, fd)}
+	}
+	return kindSchema(schemas, fd)
+}
+
+// kindSchema follows the protojson mapping, which encodes 64-bit integers as
+// strings and enums by name.
+func kindSchema(schemas map[string]interface{}, fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		var names []string
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		addSchema(schemas, fd.Message())
+		return ref(fd.Message())
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
@@ -0,0 +1,31 @@
+// Command openapi_gen writes the OpenAPI document of the HTTP/JSON gateway for
+// GreetService, ProfileService and CalculatorService, and the legacy names
+// of GreetService and CalculatorService, to stdout.
+package main
+
+import (
+	"encoding/json"
+	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/gateway"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"log"
+	"os"
+)
+
+func main() {
+	doc := gateway.OpenAPI("grpc-course gateway",
+		greetv1.File_greet_v1_greet_proto.Services().ByName("GreetService"),
+		greetv1.File_greet_v1_profile_proto.Services().ByName("ProfileService"),
+		calculatorv1.File_calculator_v1_calculator_proto.Services().ByName("CalculatorService"),
+		greetpb.File_greet_greetpb_greet_proto.Services().ByName("GreatService"),
+		calculatorpb.File_calculator_calculatorpb_calculator_proto.Services().ByName("SumService"),
+	)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		log.Fatalf("Failed to write OpenAPI document: %v", err)
+	}
+}
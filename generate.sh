@@ -1,3 +1,11 @@
-protoc greet/greetpb/greet.proto --go_out=plugins=grpc:.
+#!/bin/sh
+# Lints the protos and regenerates their Go code and the gateway's OpenAPI
+# document. Needs buf (https://buf.build/docs/installation); the protoc
+# plugins are the versions pinned in go.mod.
+set -e
+cd "$(dirname "$0")"
 
-protoc calculator/calculatorpb/calculator.proto --go_out=plugins=grpc:.
\ No newline at end of file
+buf lint
+buf generate
+
+go run ./gateway/openapi_gen > gateway/openapi.json
@@ -0,0 +1,26 @@
+module github.com/ferza17/grpc-course
+
+go 1.25.0
+
+require (
+	github.com/chzyer/readline v1.5.1
+	github.com/gorilla/websocket v1.5.3
+	github.com/klauspost/compress v1.18.0
+	go.etcd.io/bbolt v1.4.3
+	golang.org/x/time v0.15.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217
+	google.golang.org/grpc v1.79.3
+	google.golang.org/protobuf v1.36.10
+)
+
+require (
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+	google.golang.org/grpc/cmd/protoc-gen-go-grpc v1.5.1 // indirect
+)
+
+tool (
+	google.golang.org/grpc/cmd/protoc-gen-go-grpc
+	google.golang.org/protobuf/cmd/protoc-gen-go
+)
@@ -2,105 +2,187 @@ package main
 
 import (
 	"context"
+	"expvar"
+	"flag"
 	"fmt"
+	"github.com/ferza17/grpc-course/audit"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/deadline"
+	"github.com/ferza17/grpc-course/descriptor"
+	"github.com/ferza17/grpc-course/gateway"
 	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	"github.com/ferza17/grpc-course/greet/profile"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/idempotency"
+	"github.com/ferza17/grpc-course/legacy"
+	"github.com/ferza17/grpc-course/multiplex"
+	"github.com/ferza17/grpc-course/pacing"
+	"github.com/ferza17/grpc-course/ratelimit"
+	"github.com/ferza17/grpc-course/transport"
+	"github.com/ferza17/grpc-course/validate"
+	"github.com/ferza17/grpc-course/web"
 	"google.golang.org/grpc"
-	"io"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"log"
 	"net"
-	"strconv"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
+	// Embeds the time zones of profiles for hosts without a zoneinfo database.
+	_ "time/tzdata"
 )
 
-type server struct{}
+var configPath = flag.String("config", "", "path to a JSON config file")
 
-// Unary API
-func (*server) Greet(ctx context.Context, req *greetpb.GreatRequest) (*greetpb.GreetResponse, error) {
-	fmt.Printf("Greet Function was invoked with: %v", req)
-	firstName := req.GetGreeting().GetFirstName()
-	result := "Hello " + firstName
+func main() {
+	flag.Parse()
+	defaults := config.Config{
+		Addr:       "0.0.0.0:50051",
+		HTTPAddr:   "0.0.0.0:8081",
+		Reflection: true,
+		Gateway:    true,
+		GRPCWeb:    true,
+		Transport:  config.Transport{Keepalive: transport.DefaultKeepalive},
+	}
+	cfg, err := config.Load(*configPath, defaults)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := pacing.Validate(cfg.Pacing); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
 
-	res := &greetpb.GreetResponse{
-		Result: result,
+	fmt.Println("Server about to running...")
+	lis, err := transport.Listen(cfg.Addr, cfg.Transport)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	return res, nil
-}
-// Server Streaming API
-func (*server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreatService_GreetManyTimesServer) error {
-	fmt.Printf("GreetManyTimes was invoked with %v\n", req)
-	firstName := req.GetGreeting().GetFirstName()
-	for i := 0; i < 10; i++ {
-		result := "Hello " + firstName + " Number " + strconv.Itoa(i)
-		res := &greetpb.GreetManyTimesResponse{
-			Result: result,
-		}
-		if err := stream.Send(res); err != nil {
-			log.Println("Unable to send result.")
-		}
-		// TO se actually streaming but in real project you dont want to use it
-		time.Sleep(1000 * time.Millisecond)
+	authenticator, err := auth.New(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Invalid config: %v", err)
 	}
-	return nil
-}
-// Client Streaming API
-func (*server) LongGreet(stream greetpb.GreatService_LongGreetServer) error {
-	fmt.Printf("LongGreet was invoked with a streaming request %v\n", stream)
-	var result string
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			// we have finished read the client stream
-			return stream.SendAndClose(&greetpb.LongGreetResponse{
-				Result: result,
-			})
+	authenticator.TrustListener(lis.Addr())
+	tracker := legacy.NewTracker(legacy.Successors)
+	limiter := ratelimit.New(cfg.RateLimit)
+	pacer := pacing.New(cfg.Pacing)
+	dedup, err := idempotency.New(cfg.Idempotency)
+	if err != nil {
+		log.Fatalf("Failed to open idempotency store: %v", err)
+	}
+	auditor, err := audit.New(cfg.Audit)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer auditor.Close()
+	profiles := profile.NewMemoryStore()
+	if cfg.Profiles.Path != "" {
+		if profiles, err = profile.Open(cfg.Profiles.Path); err != nil {
+			log.Fatalf("Failed to open profile store: %v", err)
 		}
-
-		if err != nil {
-			log.Fatalf("Unable to read client stream: %v", err)
-			return err
+	}
+	defer profiles.Close()
+	go config.Watch(*configPath, defaults, 5*time.Second, nil, func(cfg config.Config) {
+		log.Println("Reloading rate limits")
+		limiter.Update(cfg.RateLimit)
+		if err := pacing.Validate(cfg.Pacing); err != nil {
+			log.Printf("Ignoring pacing change: %v", err)
+			return
 		}
+		log.Println("Reloading pacing")
+		pacer.Update(cfg.Pacing)
+	})
 
-		firstName := req.GetGreeting().GetFirstName()
-		result += "Hello " + firstName + "! "
+	opts := append(transport.ServerOptions(cfg.Transport),
+		grpc.ChainUnaryInterceptor(
+			authenticator.UnaryInterceptor,
+			tracker.UnaryInterceptor,
+			auditor.UnaryInterceptor,
+			limiter.UnaryInterceptor,
+			validate.UnaryInterceptor,
+			dedup.UnaryInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			authenticator.StreamInterceptor,
+			tracker.StreamInterceptor,
+			auditor.StreamInterceptor,
+			limiter.StreamInterceptor,
+			validate.StreamInterceptor,
+			deadline.StreamInterceptor(time.Duration(cfg.MaxStreamLifetime)),
+		),
+	)
+	s := grpc.NewServer(opts...)
+	greetService := greetservice.New(pacer)
+	greetService.Profiles = profiles
+	greetservice.Register(s, greetService)
+	greetv1.RegisterProfileServiceServer(s, greetservice.NewProfileServer(profiles))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	if cfg.Reflection {
+		reflection.Register(s)
 	}
-	return nil
-}
-// Bi Directional Streaming API
-func (*server) GreetEveryone(stream greetpb.GreatService_GreetEveryoneServer) error  {
-	fmt.Printf("GreetEveryone was invoked with a streaming request %v\n", stream)
 
-	for  {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
+	srv := &multiplex.Server{GRPC: s, Health: healthServer, Grace: time.Duration(cfg.ShutdownGrace)}
+	if cfg.HTTPAddr != "" || cfg.SinglePort {
+		mux := http.NewServeMux()
+		mux.Handle("/descriptor", descriptor.Handler(s))
+		mux.Handle("/debug/vars", expvar.Handler())
+		// There is no Prometheus exporter yet, /metrics is the expvar JSON.
+		mux.Handle("/metrics", expvar.Handler())
+		mux.Handle("/healthz", multiplex.Healthz(healthServer))
+		if cfg.Gateway || cfg.GRPCWeb {
+			// The HTTP handlers reach the services through the gRPC listener
+			// so every interceptor applies to HTTP traffic as well.
+			cc, err := transport.DialListener(lis)
+			if err != nil {
+				log.Fatalf("Failed to dial HTTP backend: %v", err)
+			}
+			defer cc.Close()
 
-		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
-			return err
+			services := []protoreflect.ServiceDescriptor{
+				greetv1.File_greet_v1_greet_proto.Services().ByName("GreetService"),
+				greetv1.File_greet_v1_profile_proto.Services().ByName("ProfileService"),
+				greetpb.File_greet_greetpb_greet_proto.Services().ByName("GreatService"),
+			}
+			var handler http.Handler = http.NotFoundHandler()
+			if cfg.Gateway {
+				mux.Handle("/openapi.json", gateway.OpenAPIHandler("greet", services...))
+				gw := gateway.New(cc, services...)
+				gw.MaxBodySize = int64(cfg.Transport.MaxRecvMsgSize)
+				handler = gw
+			}
+			if cfg.GRPCWeb {
+				opts := web.Options{AllowedOrigins: cfg.WebOrigins, MaxMessageSize: int64(cfg.Transport.MaxRecvMsgSize)}
+				mux.Handle(web.WebSocketPrefix+"/", web.WebSocket(cc, opts, services...))
+				handler = web.GRPCWeb(cc, opts, handler)
+			}
+			mux.Handle("/", handler)
 		}
+		srv.HTTP = mux
+	}
 
-		firstName := req.GetGreeting().GetFirstName()
-		result := "Hello " + firstName + " !"
-		if err := stream.Send(&greetpb.GreetEveryoneResponse{Result: result}); err != nil {
-			log.Fatalf("Error while sending stream.send: %v", err)
-			return err
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if cfg.SinglePort {
+		err = srv.ServeSingle(ctx, lis)
+	} else {
+		var httpLis net.Listener
+		if srv.HTTP != nil {
+			if httpLis, err = transport.Listen(cfg.HTTPAddr, cfg.Transport); err != nil {
+				log.Fatalf("Failed to listen for HTTP: %v", err)
+			}
 		}
+		err = srv.Serve(ctx, lis, httpLis)
 	}
-}
-
-func main() {
-	fmt.Println("Server about to running...")
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
-	}
-
-	s := grpc.NewServer()
-	greetpb.RegisterGreatServiceServer(s, &server{})
-
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("Failed to server: %v", err)
+		log.Fatalf("Failed to serve: %v", err)
 	}
+	log.Println("Server stopped")
 }
@@ -0,0 +1,145 @@
+// Package greetclient is a client library for greet.v1.GreetService and
+// greet.v1.ProfileService with default deadlines, retries of the reads and
+// typed errors, see rpcclient.
+package greetclient
+
+import (
+	"context"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/rpcclient"
+	"google.golang.org/grpc"
+	"io"
+)
+
+// Idempotent lists the methods that are safe to retry.
+var Idempotent = []string{
+	"/greet.v1.GreetService/Greet",
+	"/greet.v1.ProfileService/GetProfile",
+	"/greet.v1.ProfileService/ListProfiles",
+}
+
+// Client calls GreetService. Errors are *rpcclient.Error.
+type Client struct {
+	cc       *grpc.ClientConn
+	pb       greetv1.GreetServiceClient
+	profiles greetv1.ProfileServiceClient
+	opts     rpcclient.Options
+}
+
+// Dial connects to a greet_server at addr.
+func Dial(ctx context.Context, addr string, opts rpcclient.Options) (*Client, error) {
+	cc, err := rpcclient.Dial(ctx, addr, opts, Idempotent...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		cc:       cc,
+		pb:       greetv1.NewGreetServiceClient(cc),
+		profiles: greetv1.NewProfileServiceClient(cc),
+		opts:     opts,
+	}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.cc.Close()
+}
+
+// Raw returns the generated client on the same connection, for calls the
+// helpers below do not cover.
+func (c *Client) Raw() greetv1.GreetServiceClient {
+	return c.pb
+}
+
+// Greet returns the greeting for one person.
+func (c *Client) Greet(ctx context.Context, firstName, lastName string) (string, error) {
+	res, err := c.pb.Greet(ctx, &greetv1.GreetRequest{
+		Greeting: &greetv1.Greeting{FirstName: firstName, LastName: lastName},
+	})
+	if err != nil {
+		return "", err
+	}
+	return res.GetResult(), nil
+}
+
+// GreetUser returns the greeting personalised with the profile of userID,
+// in the user's language and time of day.
+func (c *Client) GreetUser(ctx context.Context, userID, firstName string) (string, error) {
+	res, err := c.pb.Greet(ctx, &greetv1.GreetRequest{
+		Greeting: &greetv1.Greeting{FirstName: firstName},
+		UserId:   userID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return res.GetResult(), nil
+}
+
+// Profiles returns the ProfileService client on the same connection.
+func (c *Client) Profiles() greetv1.ProfileServiceClient {
+	return c.profiles
+}
+
+// GreetManyTimes calls fn with every greeting the server streams back. An
+// error from fn cancels the stream and is returned.
+func (c *Client) GreetManyTimes(ctx context.Context, firstName, lastName string, fn func(string) error) error {
+	ctx, cancel := c.streamContext(ctx)
+	defer cancel()
+
+	stream, err := c.pb.GreetManyTimes(ctx, &greetv1.GreetManyTimesRequest{
+		Greeting: &greetv1.Greeting{FirstName: firstName, LastName: lastName},
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(res.GetResult()); err != nil {
+			return err
+		}
+	}
+}
+
+// LongGreet streams greetings to the server and returns its combined answer.
+func (c *Client) LongGreet(ctx context.Context, greetings []*greetv1.Greeting) (string, error) {
+	ctx, cancel := c.streamContext(ctx)
+	defer cancel()
+
+	stream, err := c.pb.LongGreet(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, g := range greetings {
+		if err := stream.Send(&greetv1.LongGreetRequest{Greeting: g}); err != nil {
+			// io.EOF means the server ended the call, CloseAndRecv has the reason.
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return "", err
+	}
+	return res.GetResult(), nil
+}
+
+// GreetEveryone opens the bidi stream. Its lifetime is the caller's: it is
+// bound to ctx only and gets no default deadline.
+func (c *Client) GreetEveryone(ctx context.Context) (greetv1.GreetService_GreetEveryoneClient, error) {
+	return c.pb.GreetEveryone(ctx)
+}
+
+func (c *Client) streamContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.opts.StreamTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.opts.StreamTimeout)
+}
@@ -0,0 +1,103 @@
+// Package greetfake is a scriptable in-process GreatService for tests of code
+// that uses greetpb.GreatServiceClient. See package fake for scripts.
+//
+//	f, client := greetfake.Start(t)
+//	f.Greet.Enqueue(fake.Send(&greetpb.GreetResponse{Result: "hi"}))
+//	... exercise code using client ...
+//	reqs := fake.Requests[*greetpb.GreatRequest](f.Greet)
+package greetfake
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/fake"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/harness"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+// Server implements greetpb.GreatServiceServer with one fake.Method per RPC.
+type Server struct {
+	Greet          *fake.Method
+	GreetManyTimes *fake.Method
+	LongGreet      *fake.Method
+	GreetEveryone  *fake.Method
+}
+
+// New returns a Server without scripts.
+func New() *Server {
+	return &Server{
+		Greet:          fake.NewMethod("/greet.GreatService/Greet"),
+		GreetManyTimes: fake.NewMethod("/greet.GreatService/GreetManyTimes"),
+		LongGreet:      fake.NewMethod("/greet.GreatService/LongGreet"),
+		GreetEveryone:  fake.NewMethod("/greet.GreatService/GreetEveryone"),
+	}
+}
+
+// Start serves a new Server on a bufconn listener for the duration of the
+// test and returns it with a client connected to it.
+func Start(tb testing.TB) (*Server, greetpb.GreatServiceClient) {
+	tb.Helper()
+	f := New()
+	h := harness.New(tb, func(s *grpc.Server) { greetpb.RegisterGreatServiceServer(s, f.Service()) })
+	return f, greetpb.NewGreatServiceClient(h.Conn)
+}
+
+// Service returns the greetpb.GreatServiceServer to register.
+func (f *Server) Service() greetpb.GreatServiceServer {
+	return service{f: f}
+}
+
+// service adapts Server to the generated interface, whose method names
+// clash with the fields of Server.
+type service struct {
+	greetpb.UnimplementedGreatServiceServer
+
+	f *Server
+}
+
+func (s service) Greet(ctx context.Context, req *greetpb.GreatRequest) (*greetpb.GreetResponse, error) {
+	res, err := fake.Unary(ctx, s.f.Greet, req)
+	if err != nil {
+		return nil, err
+	}
+	return fake.As[*greetpb.GreetResponse](res)
+}
+
+func (s service) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreatService_GreetManyTimesServer) error {
+	return fake.ServerStream(stream.Context(), s.f.GreetManyTimes, req, func(m proto.Message) error {
+		out, err := fake.As[*greetpb.GreetManyTimesResponse](m)
+		if err != nil {
+			return err
+		}
+		return stream.Send(out)
+	})
+}
+
+func (s service) LongGreet(stream greetpb.GreatService_LongGreetServer) error {
+	res, err := fake.ClientStream(stream.Context(), s.f.LongGreet, func() (proto.Message, error) {
+		return stream.Recv()
+	})
+	if err != nil {
+		return err
+	}
+	out, err := fake.As[*greetpb.LongGreetResponse](res)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(out)
+}
+
+func (s service) GreetEveryone(stream greetpb.GreatService_GreetEveryoneServer) error {
+	return fake.BiDi(stream.Context(), s.f.GreetEveryone,
+		func() (proto.Message, error) { return stream.Recv() },
+		func(m proto.Message) error {
+			out, err := fake.As[*greetpb.GreetEveryoneResponse](m)
+			if err != nil {
+				return err
+			}
+			return stream.Send(out)
+		},
+	)
+}
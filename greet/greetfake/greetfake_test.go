@@ -0,0 +1,157 @@
+package greetfake_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/fake"
+	"github.com/ferza17/grpc-course/greet/greetfake"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGreetScripts(t *testing.T) {
+	f, c := greetfake.Start(t)
+	f.Greet.
+		Enqueue(fake.Send(&greetpb.GreetResponse{Result: "first"})).
+		Enqueue(fake.Fail(status.Error(codes.Unavailable, "down")))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer t")
+	res, err := c.Greet(ctx, &greetpb.GreatRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}})
+	if err != nil || res.GetResult() != "first" {
+		t.Fatalf("Greet = %v, %v, want first", res, err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err := c.Greet(ctx, &greetpb.GreatRequest{})
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("Greet call %d error = %v, want Unavailable", i+2, err)
+		}
+	}
+
+	reqs := fake.Requests[*greetpb.GreatRequest](f.Greet)
+	if len(reqs) != 3 || reqs[0].GetGreeting().GetFirstName() != "Ann" {
+		t.Errorf("recorded requests = %v", reqs)
+	}
+	if md := f.Greet.Calls()[0].Metadata; len(md.Get("authorization")) != 1 {
+		t.Errorf("recorded metadata = %v, want authorization", md)
+	}
+}
+
+func TestGreetUnscripted(t *testing.T) {
+	_, c := greetfake.Start(t)
+	_, err := c.Greet(context.Background(), &greetpb.GreatRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Greet error = %v, want Unimplemented", err)
+	}
+}
+
+func TestGreetWrongScriptedType(t *testing.T) {
+	f, c := greetfake.Start(t)
+	f.Greet.Enqueue(fake.Send(&greetpb.LongGreetResponse{}))
+	f.GreetManyTimes.Enqueue(fake.Send(&greetpb.GreetResponse{}))
+
+	_, err := c.Greet(context.Background(), &greetpb.GreatRequest{})
+	if status.Code(err) != codes.Internal || !strings.Contains(err.Error(), "*greetpb.LongGreetResponse") {
+		t.Errorf("Greet error = %v, want Internal naming the scripted type", err)
+	}
+	stream, err := c.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Internal {
+		t.Errorf("GreetManyTimes error = %v, want Internal", err)
+	}
+}
+
+func TestGreetManyTimesFailsMidStream(t *testing.T) {
+	f, c := greetfake.Start(t)
+	f.GreetManyTimes.Enqueue(
+		fake.Send(&greetpb.GreetManyTimesResponse{Result: "a"}),
+		fake.SendAfter(10*time.Millisecond, &greetpb.GreetManyTimesResponse{Result: "b"}),
+		fake.FailAfter(10*time.Millisecond, status.Error(codes.Aborted, "boom")),
+	)
+
+	stream, err := c.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	var got []string
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if status.Code(err) != codes.Aborted {
+				t.Errorf("Recv error = %v, want Aborted", err)
+			}
+			break
+		}
+		got = append(got, res.GetResult())
+	}
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("received %v, want [a b]", got)
+	}
+}
+
+func TestGreetManyTimesCanceled(t *testing.T) {
+	f, c := greetfake.Start(t)
+	f.GreetManyTimes.Enqueue(fake.SendAfter(time.Hour, &greetpb.GreetManyTimesResponse{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	stream, err := c.GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Recv error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestLongGreetRecordsRequests(t *testing.T) {
+	f, c := greetfake.Start(t)
+	f.LongGreet.Enqueue(fake.Send(&greetpb.LongGreetResponse{Result: "done"}))
+
+	stream, err := c.LongGreet(context.Background())
+	if err != nil {
+		t.Fatalf("LongGreet: %v", err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if err := stream.Send(&greetpb.LongGreetRequest{Greeting: &greetpb.Greeting{FirstName: name}}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil || res.GetResult() != "done" {
+		t.Fatalf("CloseAndRecv = %v, %v, want done", res, err)
+	}
+	if reqs := fake.Requests[*greetpb.LongGreetRequest](f.LongGreet); len(reqs) != 3 {
+		t.Errorf("recorded %d requests, want 3", len(reqs))
+	}
+}
+
+func TestGreetEveryone(t *testing.T) {
+	f, c := greetfake.Start(t)
+	f.GreetEveryone.Enqueue(fake.Send(&greetpb.GreetEveryoneResponse{Result: "hi"}))
+
+	stream, err := c.GreetEveryone(context.Background())
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: &greetpb.Greeting{FirstName: "x"}}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	res, err := stream.Recv()
+	if err != nil || res.GetResult() != "hi" {
+		t.Fatalf("Recv = %v, %v, want hi", res, err)
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+	if reqs := fake.Requests[*greetpb.GreetEveryoneRequest](f.GreetEveryone); len(reqs) != 1 {
+		t.Errorf("recorded %d requests, want 1", len(reqs))
+	}
+}
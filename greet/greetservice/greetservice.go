@@ -0,0 +1,165 @@
+// Package greetservice implements GreetService and ProfileService, so they
+// can be served by greet_server or embedded in other servers and tests.
+package greetservice
+
+import (
+	"context"
+	"fmt"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/greet/profile"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/pacing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Server implements greet.v1.GreetService. Legacy serves it under the old
+// greet.GreatService name.
+type Server struct {
+	greetv1.UnimplementedGreetServiceServer
+
+	// Pacing spaces the GreetManyTimes messages, nil sends them back to back.
+	Pacing *pacing.Policy
+
+	// Profiles personalise the greetings of Greet requests with a user id,
+	// nil refuses such requests.
+	Profiles profile.Store
+
+	// Now is the clock of time-of-day greetings, nil means time.Now.
+	Now func() time.Time
+
+	// Out receives a line for every call served, nil means os.Stdout.
+	Out io.Writer
+}
+
+// New returns a Server paced by p.
+func New(p *pacing.Policy) *Server {
+	return &Server{Pacing: p}
+}
+
+// Register serves srv on s as greet.v1.GreetService and, until its clients
+// have migrated, as greet.GreatService.
+func Register(s grpc.ServiceRegistrar, srv *Server) {
+	greetv1.RegisterGreetServiceServer(s, srv)
+	greetpb.RegisterGreatServiceServer(s, Legacy(srv))
+}
+
+func (s *Server) printf(format string, args ...any) {
+	out := s.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	fmt.Fprintf(out, format, args...)
+}
+
+// Unary API
+func (s *Server) Greet(ctx context.Context, req *greetv1.GreetRequest) (*greetv1.GreetResponse, error) {
+	s.printf("Greet Function was invoked with: %v", req)
+	firstName := req.GetGreeting().GetFirstName()
+	result := "Hello " + firstName
+	if userID := req.GetUserId(); userID != "" {
+		if s.Profiles == nil {
+			return nil, status.Error(codes.FailedPrecondition, "this server keeps no profiles")
+		}
+		// The greeting reveals the profile, so only its user and admins
+		// may ask for it.
+		if err := authorize(ctx, userID); err != nil {
+			return nil, err
+		}
+		p, err := s.Profiles.Get(userID)
+		if err != nil {
+			return nil, storeError(userID, err)
+		}
+		result = personalGreeting(p, firstName, s.now())
+	}
+
+	res := &greetv1.GreetResponse{
+		Result: result,
+	}
+
+	return res, nil
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// Server Streaming API
+func (s *Server) GreetManyTimes(req *greetv1.GreetManyTimesRequest, stream greetv1.GreetService_GreetManyTimesServer) error {
+	s.printf("GreetManyTimes was invoked with %v\n", req)
+	pacer, err := s.Pacing.Pacer(stream.Context())
+	if err != nil {
+		return err
+	}
+	firstName := req.GetGreeting().GetFirstName()
+	for i := 0; i < 10; i++ {
+		if err := pacer.Wait(stream.Context()); err != nil {
+			return err
+		}
+		result := "Hello " + firstName + " Number " + strconv.Itoa(i)
+		res := &greetv1.GreetManyTimesResponse{
+			Result: result,
+		}
+		if err := stream.Send(res); err != nil {
+			log.Printf("Unable to send result: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Client Streaming API
+func (s *Server) LongGreet(stream greetv1.GreetService_LongGreetServer) error {
+	s.printf("LongGreet was invoked with a streaming request %v\n", stream)
+	var result string
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			// we have finished read the client stream
+			return stream.SendAndClose(&greetv1.LongGreetResponse{
+				Result: result,
+			})
+		}
+
+		if err != nil {
+			log.Printf("Unable to read client stream: %v", err)
+			return err
+		}
+
+		firstName := req.GetGreeting().GetFirstName()
+		result += "Hello " + firstName + "! "
+	}
+}
+
+// Bi Directional Streaming API
+func (s *Server) GreetEveryone(stream greetv1.GreetService_GreetEveryoneServer) error {
+	s.printf("GreetEveryone was invoked with a streaming request %v\n", stream)
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			log.Printf("Error while reading client stream: %v", err)
+			return err
+		}
+
+		firstName := req.GetGreeting().GetFirstName()
+		result := "Hello " + firstName + " !"
+		if err := stream.Send(&greetv1.GreetEveryoneResponse{Result: result}); err != nil {
+			log.Printf("Error while sending stream.send: %v", err)
+			return err
+		}
+	}
+}
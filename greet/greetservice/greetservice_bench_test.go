@@ -0,0 +1,96 @@
+package greetservice_test
+
+import (
+	"context"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"io"
+	"testing"
+)
+
+// The benchmarks run the handlers unpaced over bufconn, so they measure the
+// handlers and the gRPC stack without the network. The harness servers do
+// not print the calls.
+
+func BenchmarkGreet(b *testing.B) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(b).Conn)
+	req := &greetv1.GreetRequest{Greeting: greeting("John")}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.Greet(context.Background(), req); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkGreetManyTimes measures whole calls of ten responses each.
+func BenchmarkGreetManyTimes(b *testing.B) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(b).Conn)
+	req := &greetv1.GreetManyTimesRequest{Greeting: greeting("John")}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream, err := c.GreetManyTimes(context.Background(), req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkLongGreet measures whole calls of ten requests each.
+func BenchmarkLongGreet(b *testing.B) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(b).Conn)
+	req := &greetv1.LongGreetRequest{Greeting: greeting("John")}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream, err := c.LongGreet(context.Background())
+		if err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < 10; j++ {
+			if err := stream.Send(req); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if _, err := stream.CloseAndRecv(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGreetEveryone measures round trips on one open stream.
+func BenchmarkGreetEveryone(b *testing.B) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(b).Conn)
+	stream, err := c.GreetEveryone(context.Background())
+	if err != nil {
+		b.Fatal(err)
+	}
+	req := &greetv1.GreetEveryoneRequest{Greeting: greeting("John")}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := stream.Send(req); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		b.Fatalf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,185 @@
+package greetservice_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/pacing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// hourly waits an hour between streamed messages, so a stream can be
+// canceled after its first message.
+var hourly = pacing.New(config.Pacing{Default: config.Pace{Mode: pacing.Fixed, Interval: config.Duration(time.Hour)}})
+
+func greeting(first string) *greetv1.Greeting {
+	return &greetv1.Greeting{FirstName: first, LastName: "Doe"}
+}
+
+func TestGreet(t *testing.T) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(t).Conn)
+
+	tests := []struct {
+		name     string
+		greeting *greetv1.Greeting
+		want     string
+	}{
+		{name: "first name", greeting: greeting("John"), want: "Hello John"},
+		{name: "empty name", greeting: greeting(""), want: "Hello "},
+		{name: "no greeting", greeting: nil, want: "Hello "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := c.Greet(context.Background(), &greetv1.GreetRequest{Greeting: tt.greeting})
+			if err != nil {
+				t.Fatalf("Greet: %v", err)
+			}
+			if res.GetResult() != tt.want {
+				t.Errorf("Greet = %q, want %q", res.GetResult(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetManyTimes(t *testing.T) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(t).Conn)
+
+	stream, err := c.GreetManyTimes(context.Background(), &greetv1.GreetManyTimesRequest{Greeting: greeting("Ann")})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	for i := 0; ; i++ {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			if i != 10 {
+				t.Errorf("received %d messages, want 10", i)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if want := "Hello Ann Number " + strconv.Itoa(i); res.GetResult() != want {
+			t.Errorf("message %d = %q, want %q", i, res.GetResult(), want)
+		}
+	}
+}
+
+func TestGreetManyTimesCancel(t *testing.T) {
+	// An hourly paced server keeps the stream open long enough to cancel it midway.
+	h := harness.New(t, func(s *grpc.Server) {
+		greetservice.Register(s, greetservice.New(hourly))
+	})
+	c := greetv1.NewGreetServiceClient(h.Conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.GreetManyTimes(ctx, &greetv1.GreetManyTimesRequest{Greeting: greeting("Ann")})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("first Recv: %v", err)
+	}
+	cancel()
+
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("Recv after cancel = %v, want Canceled", err)
+	}
+}
+
+func TestLongGreet(t *testing.T) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(t).Conn)
+
+	tests := []struct {
+		name  string
+		names []string
+		want  string
+	}{
+		{name: "empty stream", names: nil, want: ""},
+		{name: "one", names: []string{"Ann"}, want: "Hello Ann! "},
+		{name: "many", names: []string{"Ann", "Bo", "Cy"}, want: "Hello Ann! Hello Bo! Hello Cy! "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.LongGreet(context.Background())
+			if err != nil {
+				t.Fatalf("LongGreet: %v", err)
+			}
+			for _, name := range tt.names {
+				if err := stream.Send(&greetv1.LongGreetRequest{Greeting: greeting(name)}); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if err != nil {
+				t.Fatalf("CloseAndRecv: %v", err)
+			}
+			if res.GetResult() != tt.want {
+				t.Errorf("LongGreet = %q, want %q", res.GetResult(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetEveryone(t *testing.T) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(t).Conn)
+
+	tests := []struct {
+		name  string
+		names []string
+	}{
+		{name: "empty stream", names: nil},
+		{name: "many", names: []string{"Ann", "Bo", "Cy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.GreetEveryone(context.Background())
+			if err != nil {
+				t.Fatalf("GreetEveryone: %v", err)
+			}
+			// Answers come one per request, so send and receive in lockstep.
+			for _, name := range tt.names {
+				if err := stream.Send(&greetv1.GreetEveryoneRequest{Greeting: greeting(name)}); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+				res, err := stream.Recv()
+				if err != nil {
+					t.Fatalf("Recv: %v", err)
+				}
+				if want := "Hello " + name + " !"; res.GetResult() != want {
+					t.Errorf("GreetEveryone = %q, want %q", res.GetResult(), want)
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("CloseSend: %v", err)
+			}
+			if _, err := stream.Recv(); err != io.EOF {
+				t.Errorf("Recv after CloseSend = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestGreetEveryoneCancel(t *testing.T) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(t).Conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	stream, err := c.GreetEveryone(ctx)
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	// Nothing is sent, the server is blocked in Recv when the deadline hits.
+	if _, err := stream.Recv(); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Recv = %v, want DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,96 @@
+package greetservice
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/legacy"
+	"google.golang.org/grpc"
+)
+
+// Legacy serves s under the pre-v1 name greet.GreatService. Requests and
+// responses are converted to and from their wire compatible v1 messages.
+func Legacy(s greetv1.GreetServiceServer) greetpb.GreatServiceServer {
+	return legacyServer{s: s}
+}
+
+type legacyServer struct {
+	greetpb.UnimplementedGreatServiceServer
+
+	s greetv1.GreetServiceServer
+}
+
+func (l legacyServer) Greet(ctx context.Context, req *greetpb.GreatRequest) (*greetpb.GreetResponse, error) {
+	var v1req greetv1.GreetRequest
+	if err := legacy.Convert(&v1req, req); err != nil {
+		return nil, err
+	}
+	v1res, err := l.s.Greet(ctx, &v1req)
+	if err != nil {
+		return nil, err
+	}
+	var res greetpb.GreetResponse
+	if err := legacy.Convert(&res, v1res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (l legacyServer) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreatService_GreetManyTimesServer) error {
+	var v1req greetv1.GreetManyTimesRequest
+	if err := legacy.Convert(&v1req, req); err != nil {
+		return err
+	}
+	return l.s.GreetManyTimes(&v1req, greetManyTimesStream{stream})
+}
+
+func (l legacyServer) LongGreet(stream greetpb.GreatService_LongGreetServer) error {
+	return l.s.LongGreet(longGreetStream{stream})
+}
+
+func (l legacyServer) GreetEveryone(stream greetpb.GreatService_GreetEveryoneServer) error {
+	return l.s.GreetEveryone(greetEveryoneStream{stream})
+}
+
+// The stream adapters hand v1 messages straight to the underlying stream,
+// which encodes them exactly like their legacy counterparts.
+
+type greetManyTimesStream struct {
+	grpc.ServerStream
+}
+
+func (s greetManyTimesStream) Send(res *greetv1.GreetManyTimesResponse) error {
+	return s.SendMsg(res)
+}
+
+type longGreetStream struct {
+	grpc.ServerStream
+}
+
+func (s longGreetStream) Recv() (*greetv1.LongGreetRequest, error) {
+	req := new(greetv1.LongGreetRequest)
+	if err := s.RecvMsg(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s longGreetStream) SendAndClose(res *greetv1.LongGreetResponse) error {
+	return s.SendMsg(res)
+}
+
+type greetEveryoneStream struct {
+	grpc.ServerStream
+}
+
+func (s greetEveryoneStream) Recv() (*greetv1.GreetEveryoneRequest, error) {
+	req := new(greetv1.GreetEveryoneRequest)
+	if err := s.RecvMsg(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s greetEveryoneStream) Send(res *greetv1.GreetEveryoneResponse) error {
+	return s.SendMsg(res)
+}
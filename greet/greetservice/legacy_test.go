@@ -0,0 +1,87 @@
+package greetservice_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/harness"
+	"io"
+	"testing"
+)
+
+// The legacy service shares the implementation, so these tests only check
+// that every message makes it through the adapter.
+
+func TestLegacyGreet(t *testing.T) {
+	c := greetpb.NewGreatServiceClient(harness.Greet(t).Conn)
+	res, err := c.Greet(context.Background(), &greetpb.GreatRequest{Greeting: &greetpb.Greeting{FirstName: "John"}})
+	if err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	if res.GetResult() != "Hello John" {
+		t.Errorf("Greet = %q, want %q", res.GetResult(), "Hello John")
+	}
+}
+
+func TestLegacyGreetManyTimes(t *testing.T) {
+	c := greetpb.NewGreatServiceClient(harness.Greet(t).Conn)
+	stream, err := c.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	var got []string
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		got = append(got, res.GetResult())
+	}
+	if len(got) != 10 || got[9] != "Hello Ann Number 9" {
+		t.Errorf("GreetManyTimes = %q, want ten greetings of Ann", got)
+	}
+}
+
+func TestLegacyLongGreet(t *testing.T) {
+	c := greetpb.NewGreatServiceClient(harness.Greet(t).Conn)
+	stream, err := c.LongGreet(context.Background())
+	if err != nil {
+		t.Fatalf("LongGreet: %v", err)
+	}
+	for _, name := range []string{"A", "B"} {
+		if err := stream.Send(&greetpb.LongGreetRequest{Greeting: &greetpb.Greeting{FirstName: name}}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv: %v", err)
+	}
+	if want := "Hello A! Hello B! "; res.GetResult() != want {
+		t.Errorf("LongGreet = %q, want %q", res.GetResult(), want)
+	}
+}
+
+func TestLegacyGreetEveryone(t *testing.T) {
+	c := greetpb.NewGreatServiceClient(harness.Greet(t).Conn)
+	stream, err := c.GreetEveryone(context.Background())
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: &greetpb.Greeting{FirstName: "Bo"}}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if want := "Hello Bo !"; res.GetResult() != want {
+		t.Errorf("GreetEveryone = %q, want %q", res.GetResult(), want)
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+}
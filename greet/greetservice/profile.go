@@ -0,0 +1,215 @@
+package greetservice
+
+import (
+	"context"
+	"errors"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/greet/profile"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+// defaultPageSize is the ListProfiles page size when the request has none,
+// maxPageSize the largest the request may ask for.
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// ProfileServer implements greet.v1.ProfileService over a profile.Store.
+// Users manage their own profile, the one whose user id is the subject of
+// their verified bearer token; admins, see config.Auth.Admins, manage every
+// profile and are the only ones who may list them. It relies on an
+// auth.Authenticator in the server's interceptor chain.
+type ProfileServer struct {
+	greetv1.UnimplementedProfileServiceServer
+
+	Store profile.Store
+}
+
+// NewProfileServer returns a ProfileServer managing the profiles in store.
+func NewProfileServer(store profile.Store) *ProfileServer {
+	return &ProfileServer{Store: store}
+}
+
+func (s *ProfileServer) CreateProfile(ctx context.Context, req *greetv1.CreateProfileRequest) (*greetv1.CreateProfileResponse, error) {
+	p := req.GetProfile()
+	if err := authorize(ctx, p.GetUserId()); err != nil {
+		return nil, err
+	}
+	if err := checkTimeZone("profile.time_zone", p.GetTimeZone()); err != nil {
+		return nil, err
+	}
+	if err := s.Store.Create(p); err != nil {
+		return nil, storeError(p.GetUserId(), err)
+	}
+	return &greetv1.CreateProfileResponse{Profile: p}, nil
+}
+
+func (s *ProfileServer) GetProfile(ctx context.Context, req *greetv1.GetProfileRequest) (*greetv1.GetProfileResponse, error) {
+	if err := authorize(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+	p, err := s.Store.Get(req.GetUserId())
+	if err != nil {
+		return nil, storeError(req.GetUserId(), err)
+	}
+	return &greetv1.GetProfileResponse{Profile: p}, nil
+}
+
+func (s *ProfileServer) UpdateProfile(ctx context.Context, req *greetv1.UpdateProfileRequest) (*greetv1.UpdateProfileResponse, error) {
+	if err := authorize(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+	if req.TimeZone != nil {
+		if err := checkTimeZone("time_zone", req.GetTimeZone()); err != nil {
+			return nil, err
+		}
+	}
+	p, err := s.Store.Update(req.GetUserId(), func(p *greetv1.Profile) error {
+		if req.PreferredName != nil {
+			p.PreferredName = req.GetPreferredName()
+		}
+		if req.Locale != nil {
+			p.Locale = req.GetLocale()
+		}
+		if req.Pronouns != nil {
+			p.Pronouns = req.GetPronouns()
+		}
+		if req.TimeZone != nil {
+			p.TimeZone = req.GetTimeZone()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, storeError(req.GetUserId(), err)
+	}
+	return &greetv1.UpdateProfileResponse{Profile: p}, nil
+}
+
+func (s *ProfileServer) DeleteProfile(ctx context.Context, req *greetv1.DeleteProfileRequest) (*greetv1.DeleteProfileResponse, error) {
+	if err := authorize(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+	if err := s.Store.Delete(req.GetUserId()); err != nil {
+		return nil, storeError(req.GetUserId(), err)
+	}
+	return &greetv1.DeleteProfileResponse{}, nil
+}
+
+// ListProfiles uses the last user id of a page as the token of the next.
+func (s *ProfileServer) ListProfiles(ctx context.Context, req *greetv1.ListProfilesRequest) (*greetv1.ListProfilesResponse, error) {
+	if err := authorize(ctx, ""); err != nil {
+		return nil, err
+	}
+	// Servers without validate.UnaryInterceptor may see any size.
+	size := int(req.GetPageSize())
+	switch {
+	case size <= 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+	// One more than asked tells whether there is a next page.
+	profiles, err := s.Store.List(req.GetPageToken(), size+1)
+	if err != nil {
+		return nil, storeError("", err)
+	}
+	res := &greetv1.ListProfilesResponse{Profiles: profiles}
+	if len(profiles) > size {
+		res.Profiles = profiles[:size]
+		res.NextPageToken = profiles[size-1].GetUserId()
+	}
+	return res, nil
+}
+
+// authorize lets admins through, and the user userID, unless it is empty.
+func authorize(ctx context.Context, userID string) error {
+	if auth.IsAdmin(ctx) {
+		return nil
+	}
+	id, ok := auth.Verified(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "profiles need a bearer token or an API key")
+	}
+	if userID == "" {
+		return status.Error(codes.PermissionDenied, "only admins may list profiles")
+	}
+	if id != "sub:"+userID {
+		return status.Errorf(codes.PermissionDenied, "only user %q and admins may manage this profile", userID)
+	}
+	return nil
+}
+
+// storeError converts the errors of profile.Store to status errors.
+func storeError(userID string, err error) error {
+	switch {
+	case errors.Is(err, profile.ErrNotFound):
+		return status.Errorf(codes.NotFound, "no profile for user %q", userID)
+	case errors.Is(err, profile.ErrExists):
+		return status.Errorf(codes.AlreadyExists, "user %q already has a profile", userID)
+	}
+	return status.Errorf(codes.Internal, "profile store: %v", err)
+}
+
+// checkTimeZone refuses time zones missing from the IANA database.
+func checkTimeZone(field, name string) error {
+	if _, err := time.LoadLocation(name); err == nil {
+		return nil
+	}
+	st := status.Newf(codes.InvalidArgument, "unknown time zone %q", name)
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+		{Field: field, Description: "must be an IANA time zone such as Asia/Jakarta"},
+	}}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// salutations are the words Greet opens with for users with a profile.
+// Anytime is used when the user's time of day is unknown.
+type salutations struct {
+	morning, afternoon, evening, anytime string
+}
+
+// languages are keyed by the language subtag of the profile locale.
+var languages = map[string]salutations{
+	"en": {"Good morning", "Good afternoon", "Good evening", "Hello"},
+	"de": {"Guten Morgen", "Guten Tag", "Guten Abend", "Hallo"},
+	"es": {"Buenos días", "Buenas tardes", "Buenas noches", "Hola"},
+	"fr": {"Bonjour", "Bonjour", "Bonsoir", "Bonjour"},
+	"id": {"Selamat pagi", "Selamat siang", "Selamat malam", "Halo"},
+}
+
+// personalGreeting greets p, or firstName when p has no preferred name, in
+// the language of p.Locale for the time of day now is in p.TimeZone.
+func personalGreeting(p *greetv1.Profile, firstName string, now time.Time) string {
+	lang, _, _ := strings.Cut(strings.ReplaceAll(p.GetLocale(), "_", "-"), "-")
+	words, ok := languages[strings.ToLower(lang)]
+	if !ok {
+		words = languages["en"]
+	}
+
+	salutation := words.anytime
+	// An empty time zone would load as UTC.
+	if loc, err := time.LoadLocation(p.GetTimeZone()); err == nil && p.GetTimeZone() != "" {
+		switch hour := now.In(loc).Hour(); {
+		case hour >= 5 && hour < 12:
+			salutation = words.morning
+		case hour >= 12 && hour < 18:
+			salutation = words.afternoon
+		default:
+			salutation = words.evening
+		}
+	}
+
+	name := p.GetPreferredName()
+	if name == "" {
+		name = firstName
+	}
+	return salutation + ", " + name
+}
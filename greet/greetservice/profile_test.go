@@ -0,0 +1,236 @@
+package greetservice_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	"github.com/ferza17/grpc-course/greet/profile"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// noonUTC is 07:00 in New York, 12:00 in London and 19:00 in Jakarta.
+var noonUTC = time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+// profiles serves GreetService and ProfileService over one memory store.
+func profiles(t *testing.T) (greetv1.GreetServiceClient, greetv1.ProfileServiceClient) {
+	store := profile.NewMemoryStore()
+	a, err := auth.New(config.Auth{APIKeys: []string{"admin-key"}, Admins: []string{"key:admin-key"}, JWTSecret: jwtSecret})
+	if err != nil {
+		t.Fatalf("auth.New: %v", err)
+	}
+	h := harness.New(t, func(s *grpc.Server) {
+		greetservice.Register(s, &greetservice.Server{Profiles: store, Now: func() time.Time { return noonUTC }})
+		greetv1.RegisterProfileServiceServer(s, greetservice.NewProfileServer(store))
+	}, grpc.UnaryInterceptor(a.UnaryInterceptor))
+	return greetv1.NewGreetServiceClient(h.Conn), greetv1.NewProfileServiceClient(h.Conn)
+}
+
+const jwtSecret = "jwt-secret"
+
+// asAdmin returns a context calling with the admin's API key.
+func asAdmin() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, "admin-key")
+}
+
+// asUser returns a context calling with a bearer token for user.
+func asUser(user string) context.Context {
+	signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"`+user+`"}`))
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte(signed))
+	token := signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestProfileCRUD(t *testing.T) {
+	_, c := profiles(t)
+	ctx := asAdmin()
+
+	ann := &greetv1.Profile{UserId: "ann", PreferredName: "Annie", Locale: "en-US", Pronouns: "she/her", TimeZone: "America/New_York"}
+	if _, err := c.CreateProfile(ctx, &greetv1.CreateProfileRequest{Profile: ann}); err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	if _, err := c.CreateProfile(ctx, &greetv1.CreateProfileRequest{Profile: ann}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("second CreateProfile = %v, want AlreadyExists", err)
+	}
+	res, err := c.GetProfile(ctx, &greetv1.GetProfileRequest{UserId: "ann"})
+	if err != nil || !proto.Equal(res.GetProfile(), ann) {
+		t.Errorf("GetProfile = %v, %v; want %v", res, err, ann)
+	}
+
+	// Absent fields are kept, present ones replaced, even by empty strings.
+	upd, err := c.UpdateProfile(ctx, &greetv1.UpdateProfileRequest{
+		UserId:        "ann",
+		PreferredName: proto.String(""),
+		TimeZone:      proto.String("Asia/Jakarta"),
+	})
+	want := &greetv1.Profile{UserId: "ann", Locale: "en-US", Pronouns: "she/her", TimeZone: "Asia/Jakarta"}
+	if err != nil || !proto.Equal(upd.GetProfile(), want) {
+		t.Errorf("UpdateProfile = %v, %v; want %v", upd, err, want)
+	}
+
+	if _, err := c.DeleteProfile(ctx, &greetv1.DeleteProfileRequest{UserId: "ann"}); err != nil {
+		t.Errorf("DeleteProfile: %v", err)
+	}
+	for name, err := range map[string]error{
+		"GetProfile":    func() error { _, err := c.GetProfile(ctx, &greetv1.GetProfileRequest{UserId: "ann"}); return err }(),
+		"UpdateProfile": func() error { _, err := c.UpdateProfile(ctx, &greetv1.UpdateProfileRequest{UserId: "ann"}); return err }(),
+		"DeleteProfile": func() error { _, err := c.DeleteProfile(ctx, &greetv1.DeleteProfileRequest{UserId: "ann"}); return err }(),
+	} {
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("%s after delete = %v, want NotFound", name, err)
+		}
+	}
+}
+
+func TestProfileAuthorization(t *testing.T) {
+	_, c := profiles(t)
+	ann := asUser("ann")
+
+	if _, err := c.CreateProfile(ann, &greetv1.CreateProfileRequest{Profile: &greetv1.Profile{UserId: "ann"}}); err != nil {
+		t.Fatalf("CreateProfile of own profile: %v", err)
+	}
+	if _, err := c.GetProfile(ann, &greetv1.GetProfileRequest{UserId: "ann"}); err != nil {
+		t.Errorf("GetProfile of own profile: %v", err)
+	}
+	if _, err := c.CreateProfile(asAdmin(), &greetv1.CreateProfileRequest{Profile: &greetv1.Profile{UserId: "bo"}}); err != nil {
+		t.Fatalf("CreateProfile as admin: %v", err)
+	}
+
+	for name, err := range map[string]error{
+		"CreateProfile": func() error {
+			_, err := c.CreateProfile(ann, &greetv1.CreateProfileRequest{Profile: &greetv1.Profile{UserId: "cy"}})
+			return err
+		}(),
+		"GetProfile":    func() error { _, err := c.GetProfile(ann, &greetv1.GetProfileRequest{UserId: "bo"}); return err }(),
+		"UpdateProfile": func() error { _, err := c.UpdateProfile(ann, &greetv1.UpdateProfileRequest{UserId: "bo"}); return err }(),
+		"DeleteProfile": func() error { _, err := c.DeleteProfile(ann, &greetv1.DeleteProfileRequest{UserId: "bo"}); return err }(),
+		"ListProfiles":  func() error { _, err := c.ListProfiles(ann, &greetv1.ListProfilesRequest{}); return err }(),
+	} {
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s of another user = %v, want PermissionDenied", name, err)
+		}
+	}
+	if _, err := c.GetProfile(context.Background(), &greetv1.GetProfileRequest{UserId: "ann"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("anonymous GetProfile = %v, want Unauthenticated", err)
+	}
+}
+
+func TestProfileTimeZone(t *testing.T) {
+	_, c := profiles(t)
+	ctx := asAdmin()
+
+	_, err := c.CreateProfile(ctx, &greetv1.CreateProfileRequest{Profile: &greetv1.Profile{UserId: "ann", TimeZone: "Mars/Olympus_Mons"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateProfile with unknown time zone = %v, want InvalidArgument", err)
+	}
+	if _, err := c.CreateProfile(ctx, &greetv1.CreateProfileRequest{Profile: &greetv1.Profile{UserId: "ann"}}); err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	_, err = c.UpdateProfile(ctx, &greetv1.UpdateProfileRequest{UserId: "ann", TimeZone: proto.String("Mars/Olympus_Mons")})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateProfile with unknown time zone = %v, want InvalidArgument", err)
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	_, c := profiles(t)
+	ctx := asAdmin()
+	for _, id := range []string{"cy", "ann", "bo"} {
+		if _, err := c.CreateProfile(ctx, &greetv1.CreateProfileRequest{Profile: &greetv1.Profile{UserId: id}}); err != nil {
+			t.Fatalf("CreateProfile: %v", err)
+		}
+	}
+
+	var pages [][]string
+	req := &greetv1.ListProfilesRequest{PageSize: 2}
+	for {
+		res, err := c.ListProfiles(ctx, req)
+		if err != nil {
+			t.Fatalf("ListProfiles: %v", err)
+		}
+		var page []string
+		for _, p := range res.GetProfiles() {
+			page = append(page, p.GetUserId())
+		}
+		pages = append(pages, page)
+		if res.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = res.GetNextPageToken()
+	}
+	if want := [][]string{{"ann", "bo"}, {"cy"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+	// The server has no validate.UnaryInterceptor to refuse the size.
+	res, err := c.ListProfiles(ctx, &greetv1.ListProfilesRequest{PageSize: -1})
+	if err != nil || len(res.GetProfiles()) != 3 {
+		t.Errorf("ListProfiles with a negative page size = %v, %v; want the default page", res, err)
+	}
+}
+
+func TestGreetUser(t *testing.T) {
+	g, c := profiles(t)
+	ctx := asAdmin()
+	for _, p := range []*greetv1.Profile{
+		{UserId: "ann", PreferredName: "Annie", TimeZone: "America/New_York"},
+		{UserId: "bo", Locale: "en-GB", TimeZone: "Europe/London"},
+		{UserId: "cy", Locale: "id_ID", TimeZone: "Asia/Jakarta"},
+		{UserId: "di", Locale: "fr"},
+		{UserId: "ed", Locale: "tlh", TimeZone: "Asia/Jakarta"},
+	} {
+		if _, err := c.CreateProfile(ctx, &greetv1.CreateProfileRequest{Profile: p}); err != nil {
+			t.Fatalf("CreateProfile: %v", err)
+		}
+	}
+
+	for _, tt := range []struct {
+		user string
+		want string
+	}{
+		{"", "Hello Name"},
+		{"ann", "Good morning, Annie"},
+		{"bo", "Good afternoon, Name"},
+		{"cy", "Selamat malam, Name"},
+		{"di", "Bonjour, Name"},
+		{"ed", "Good evening, Name"},
+	} {
+		res, err := g.Greet(ctx, &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Name"}, UserId: tt.user})
+		if err != nil || res.GetResult() != tt.want {
+			t.Errorf("Greet(user %q) = %v, %v; want %q", tt.user, res, err, tt.want)
+		}
+	}
+
+	_, err := g.Greet(ctx, &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Name"}, UserId: "zed"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Greet of user without profile = %v, want NotFound", err)
+	}
+	if _, err := g.Greet(asUser("ann"), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Name"}, UserId: "ann"}); err != nil {
+		t.Errorf("Greet of ann by ann: %v", err)
+	}
+	_, err = g.Greet(asUser("ann"), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Name"}, UserId: "bo"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Greet of bo by ann = %v, want PermissionDenied", err)
+	}
+	_, err = g.Greet(context.Background(), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Name"}, UserId: "zed"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("anonymous Greet with a user id = %v, want Unauthenticated", err)
+	}
+	_, err = greetv1.NewGreetServiceClient(harness.Greet(t).Conn).Greet(ctx, &greetv1.GreetRequest{UserId: "ann"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Greet with user id on a server without profiles = %v, want FailedPrecondition", err)
+	}
+}
@@ -0,0 +1,128 @@
+package profile
+
+import (
+	"bytes"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+	"time"
+)
+
+// bucket holds the encoded profiles keyed by user id.
+var bucket = []byte("profiles")
+
+type boltStore struct {
+	db *bolt.DB
+}
+
+// Open returns a Store keeping profiles in the bbolt file at path, created if
+// missing. Only one process may have the file open, Open waits a second for
+// another to close it before failing.
+func Open(path string) (Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) Create(p *greetv1.Profile) error {
+	data, err := proto.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b.Get([]byte(p.GetUserId())) != nil {
+			return ErrExists
+		}
+		return b.Put([]byte(p.GetUserId()), data)
+	})
+}
+
+func (s *boltStore) Get(userID string) (*greetv1.Profile, error) {
+	p := new(greetv1.Profile)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get([]byte(userID))
+		if data == nil {
+			return ErrNotFound
+		}
+		return proto.Unmarshal(data, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *boltStore) Update(userID string, fn func(*greetv1.Profile) error) (*greetv1.Profile, error) {
+	p := new(greetv1.Profile)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		data := b.Get([]byte(userID))
+		if data == nil {
+			return ErrNotFound
+		}
+		if err := proto.Unmarshal(data, p); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+		p.UserId = userID
+		data, err := proto.Marshal(p)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(userID), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *boltStore) Delete(userID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b.Get([]byte(userID)) == nil {
+			return ErrNotFound
+		}
+		return b.Delete([]byte(userID))
+	})
+}
+
+func (s *boltStore) List(after string, limit int) ([]*greetv1.Profile, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	var profiles []*greetv1.Profile
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		k, data := c.Seek([]byte(after))
+		if k != nil && bytes.Equal(k, []byte(after)) {
+			k, data = c.Next()
+		}
+		for ; k != nil && len(profiles) < limit; k, data = c.Next() {
+			p := new(greetv1.Profile)
+			if err := proto.Unmarshal(data, p); err != nil {
+				return err
+			}
+			profiles = append(profiles, p)
+		}
+		return nil
+	})
+	return profiles, err
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
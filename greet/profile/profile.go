@@ -0,0 +1,125 @@
+// Package profile stores the greet.v1.Profile of each user, in memory or in
+// a bbolt file that survives restarts, for greetservice.ProfileServer and
+// the personalised greetings of Greet.
+package profile
+
+import (
+	"errors"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"google.golang.org/protobuf/proto"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrNotFound is returned for users without a profile.
+	ErrNotFound = errors.New("profile not found")
+	// ErrExists is returned by Create for users who have a profile.
+	ErrExists = errors.New("profile already exists")
+)
+
+// Store keeps profiles keyed by user id. Profiles passed in and returned
+// are copies. Stores are safe for concurrent use.
+type Store interface {
+	// Create adds p, it fails with ErrExists if p.UserId has a profile.
+	Create(p *greetv1.Profile) error
+	// Get returns the profile of userID.
+	Get(userID string) (*greetv1.Profile, error)
+	// Update lets fn change the profile of userID and stores the result,
+	// unless fn fails. The user id must not be changed.
+	Update(userID string, fn func(*greetv1.Profile) error) (*greetv1.Profile, error)
+	// Delete removes the profile of userID.
+	Delete(userID string) error
+	// List returns up to limit profiles ordered by user id, starting after
+	// the user id after. A limit below one returns none.
+	List(after string, limit int) ([]*greetv1.Profile, error)
+	// Close releases the store.
+	Close() error
+}
+
+type memoryStore struct {
+	mu       sync.Mutex
+	profiles map[string]*greetv1.Profile
+}
+
+// NewMemoryStore returns a Store that keeps profiles in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{profiles: map[string]*greetv1.Profile{}}
+}
+
+func (s *memoryStore) Create(p *greetv1.Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.profiles[p.GetUserId()]; ok {
+		return ErrExists
+	}
+	s.profiles[p.GetUserId()] = clone(p)
+	return nil
+}
+
+func (s *memoryStore) Get(userID string) (*greetv1.Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.profiles[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return clone(p), nil
+}
+
+func (s *memoryStore) Update(userID string, fn func(*greetv1.Profile) error) (*greetv1.Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.profiles[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	p = clone(p)
+	if err := fn(p); err != nil {
+		return nil, err
+	}
+	p.UserId = userID
+	s.profiles[userID] = p
+	return clone(p), nil
+}
+
+func (s *memoryStore) Delete(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.profiles[userID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.profiles, userID)
+	return nil
+}
+
+func (s *memoryStore) List(after string, limit int) ([]*greetv1.Profile, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id := range s.profiles {
+		if id > after {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	profiles := make([]*greetv1.Profile, len(ids))
+	for i, id := range ids {
+		profiles[i] = clone(s.profiles[id])
+	}
+	return profiles, nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+func clone(p *greetv1.Profile) *greetv1.Profile {
+	return proto.Clone(p).(*greetv1.Profile)
+}
@@ -0,0 +1,136 @@
+package profile_test
+
+import (
+	"errors"
+	"github.com/ferza17/grpc-course/greet/profile"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"google.golang.org/protobuf/proto"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func stores(t *testing.T) map[string]profile.Store {
+	bolt, err := profile.Open(filepath.Join(t.TempDir(), "profiles.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { bolt.Close() })
+	return map[string]profile.Store{"memory": profile.NewMemoryStore(), "bolt": bolt}
+}
+
+func ids(profiles []*greetv1.Profile) []string {
+	var ids []string
+	for _, p := range profiles {
+		ids = append(ids, p.GetUserId())
+	}
+	return ids
+}
+
+func TestStore(t *testing.T) {
+	for name, s := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ann := &greetv1.Profile{UserId: "ann", PreferredName: "Annie", Locale: "en-GB", TimeZone: "Europe/London"}
+			if err := s.Create(ann); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if err := s.Create(&greetv1.Profile{UserId: "ann"}); !errors.Is(err, profile.ErrExists) {
+				t.Errorf("second Create = %v, want ErrExists", err)
+			}
+			got, err := s.Get("ann")
+			if err != nil || !proto.Equal(got, ann) {
+				t.Errorf("Get = %v, %v; want %v", got, err, ann)
+			}
+			// Returned profiles are copies.
+			got.PreferredName = "changed"
+			if again, _ := s.Get("ann"); again.GetPreferredName() != "Annie" {
+				t.Errorf("changing a returned profile changed the store")
+			}
+
+			got, err = s.Update("ann", func(p *greetv1.Profile) error {
+				p.Pronouns = "she/her"
+				p.UserId = "bob"
+				return nil
+			})
+			if err != nil || got.GetPronouns() != "she/her" || got.GetUserId() != "ann" {
+				t.Errorf("Update = %v, %v; want pronouns she/her on ann", got, err)
+			}
+			failed := errors.New("failed")
+			if _, err := s.Update("ann", func(p *greetv1.Profile) error {
+				p.Pronouns = "they/them"
+				return failed
+			}); err != failed {
+				t.Errorf("failed Update = %v, want %v", err, failed)
+			}
+			if got, _ := s.Get("ann"); got.GetPronouns() != "she/her" {
+				t.Errorf("failed Update stored pronouns %q", got.GetPronouns())
+			}
+			if _, err := s.Update("cy", func(*greetv1.Profile) error { return nil }); !errors.Is(err, profile.ErrNotFound) {
+				t.Errorf("Update of missing profile = %v, want ErrNotFound", err)
+			}
+
+			if err := s.Delete("ann"); err != nil {
+				t.Errorf("Delete: %v", err)
+			}
+			if _, err := s.Get("ann"); !errors.Is(err, profile.ErrNotFound) {
+				t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+			}
+			if err := s.Delete("ann"); !errors.Is(err, profile.ErrNotFound) {
+				t.Errorf("second Delete = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	for name, s := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, id := range []string{"d", "b", "a", "c", "e"} {
+				if err := s.Create(&greetv1.Profile{UserId: id}); err != nil {
+					t.Fatalf("Create: %v", err)
+				}
+			}
+			for _, tt := range []struct {
+				after string
+				limit int
+				want  []string
+			}{
+				{"", 2, []string{"a", "b"}},
+				{"b", 2, []string{"c", "d"}},
+				{"bb", 10, []string{"c", "d", "e"}},
+				{"e", 10, nil},
+				{"", 0, nil},
+				{"", -1, nil},
+			} {
+				got, err := s.List(tt.after, tt.limit)
+				if err != nil {
+					t.Fatalf("List: %v", err)
+				}
+				if !reflect.DeepEqual(ids(got), tt.want) {
+					t.Errorf("List(%q, %d) = %v, want %v", tt.after, tt.limit, ids(got), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenKeepsProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.db")
+	s, err := profile.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := s.Create(&greetv1.Profile{UserId: "ann", TimeZone: "Asia/Jakarta"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	s.Close()
+
+	s, err = profile.Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.Close()
+	if p, err := s.Get("ann"); err != nil || p.GetTimeZone() != "Asia/Jakarta" {
+		t.Errorf("Get after reopen = %v, %v; want the stored profile", p, err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package harness starts gRPC servers on in-memory bufconn listeners for
+// tests. It is meant for anyone embedding the greet and calculator services,
+// not only for this repository:
+//
+//	func TestMine(t *testing.T) {
+//		c := calculatorv1.NewCalculatorServiceClient(harness.Calculator(t).Conn)
+//		...
+//	}
+//
+// Servers and connections are shut down through tb.Cleanup.
+package harness
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/calculator/calcservice"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"testing"
+)
+
+const bufSize = 1 << 20
+
+// Harness is a running server and a client connection to it.
+type Harness struct {
+	Server *grpc.Server
+	Conn   *grpc.ClientConn
+
+	lis *bufconn.Listener
+}
+
+// New starts a server with opts, lets register add services to it, and dials
+// it. Use Dial for connections with other options.
+func New(tb testing.TB, register func(*grpc.Server), opts ...grpc.ServerOption) *Harness {
+	tb.Helper()
+
+	h := &Harness{Server: grpc.NewServer(opts...), lis: bufconn.Listen(bufSize)}
+	register(h.Server)
+	go h.Server.Serve(h.lis)
+	tb.Cleanup(h.Server.Stop)
+
+	h.Conn = h.Dial(tb)
+	return h
+}
+
+// Dial opens another connection to the server, closed on cleanup.
+func (h *Harness) Dial(tb testing.TB, opts ...grpc.DialOption) *grpc.ClientConn {
+	tb.Helper()
+
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return h.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	cc, err := grpc.NewClient("passthrough:///bufconn", opts...)
+	if err != nil {
+		tb.Fatalf("dial bufconn: %v", err)
+	}
+	tb.Cleanup(func() { cc.Close() })
+	return cc
+}
+
+// Greet serves GreetService, under its v1 and legacy names, without pacing
+// between streamed messages and without printing the calls.
+func Greet(tb testing.TB, opts ...grpc.ServerOption) *Harness {
+	tb.Helper()
+	return New(tb, func(s *grpc.Server) {
+		greetservice.Register(s, &greetservice.Server{Out: io.Discard})
+	}, opts...)
+}
+
+// Calculator serves CalculatorService, under its v1 and legacy names,
+// without pacing between streamed messages and without printing the calls.
+func Calculator(tb testing.TB, opts ...grpc.ServerOption) *Harness {
+	tb.Helper()
+	return New(tb, func(s *grpc.Server) {
+		calcservice.Register(s, &calcservice.Server{Out: io.Discard})
+	}, opts...)
+}
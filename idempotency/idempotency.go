@@ -0,0 +1,195 @@
+// Package idempotency lets clients retry unary calls without running them
+// twice. A call with an idempotency-key header runs once per caller, method
+// and key: its response is stored for a TTL and returned to every duplicate,
+// which also gets the idempotent-replayed header. Reusing a key for a
+// different request fails with FailedPrecondition. Failed calls are not
+// stored, so a retry after an error runs the call again.
+//
+// Callers are told apart by auth.Identity, so an auth.Authenticator has to
+// run first: without it every caller is named by its peer address, and the
+// clients of the HTTP gateway or of proxy_server would share their keys.
+// Duplicates arriving while the first call runs wait for it; with a file
+// store shared by several servers that only holds within each server.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// Header is the request metadata key carrying the idempotency key.
+	Header = "idempotency-key"
+	// ReplayedHeader is set to "true" on responses returned from the store.
+	ReplayedHeader = "idempotent-replayed"
+	// DefaultTTL is how long responses are kept when the config has no TTL.
+	DefaultTTL = 24 * time.Hour
+)
+
+// maxKeyLen bounds keys, which are otherwise opaque to the server.
+const maxKeyLen = 255
+
+// Deduplicator runs each keyed call once and replays its response.
+type Deduplicator struct {
+	store Store
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	running map[string]chan struct{}
+}
+
+// New returns a Deduplicator keeping responses as cfg says.
+func New(cfg config.Idempotency) (*Deduplicator, error) {
+	store := NewMemoryStore(cfg.MaxEntries)
+	if cfg.Dir != "" {
+		var err error
+		if store, err = NewFileStore(cfg.Dir); err != nil {
+			return nil, err
+		}
+	}
+	return NewWithStore(store, time.Duration(cfg.TTL)), nil
+}
+
+// NewWithStore returns a Deduplicator keeping responses in store for ttl,
+// DefaultTTL if zero.
+func NewWithStore(store Store, ttl time.Duration) *Deduplicator {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Deduplicator{store: store, ttl: ttl, running: map[string]chan struct{}{}}
+}
+
+// UnaryInterceptor deduplicates calls carrying Header. Calls without it are
+// passed through.
+func (d *Deduplicator) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get(Header)
+	if len(keys) == 0 || keys[0] == "" {
+		return handler(ctx, req)
+	}
+	if len(keys[0]) > maxKeyLen {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is longer than %d bytes", Header, maxKeyLen)
+	}
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	hash, err := requestHash(msg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "idempotency: %v", err)
+	}
+	key := storeKey(auth.Identity(ctx), info.FullMethod, keys[0])
+
+	if err := d.begin(ctx, key); err != nil {
+		return nil, err
+	}
+	defer d.end(key)
+
+	e, ok, err := d.store.Get(key)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "idempotency: %v", err)
+	}
+	if ok {
+		return replay(ctx, e, hash, keys[0])
+	}
+
+	res, err := handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if out, ok := res.(proto.Message); ok {
+		d.put(key, hash, out)
+	}
+	return res, nil
+}
+
+// begin waits until no other call with key runs, then marks it running.
+func (d *Deduplicator) begin(ctx context.Context, key string) error {
+	for {
+		d.mu.Lock()
+		done, busy := d.running[key]
+		if !busy {
+			d.running[key] = make(chan struct{})
+			d.mu.Unlock()
+			return nil
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-done:
+		}
+	}
+}
+
+func (d *Deduplicator) end(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	close(d.running[key])
+	delete(d.running, key)
+}
+
+// put stores res. The call has succeeded, so a store failure is only logged:
+// a duplicate will run the call again.
+func (d *Deduplicator) put(key string, hash []byte, res proto.Message) {
+	data, err := proto.Marshal(res)
+	if err == nil {
+		err = d.store.Put(key, Entry{
+			RequestHash: hash,
+			Type:        string(res.ProtoReflect().Descriptor().FullName()),
+			Response:    data,
+			Expires:     time.Now().Add(d.ttl),
+		})
+	}
+	if err != nil {
+		log.Printf("idempotency: storing response: %v", err)
+	}
+}
+
+func replay(ctx context.Context, e Entry, hash []byte, key string) (any, error) {
+	if !bytes.Equal(e.RequestHash, hash) {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s %q was used for a different request", Header, key)
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(e.Type))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "idempotency: stored response: %v", err)
+	}
+	res := mt.New().Interface()
+	if err := proto.Unmarshal(e.Response, res); err != nil {
+		return nil, status.Errorf(codes.Internal, "idempotency: stored response: %v", err)
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(ReplayedHeader, "true"))
+	return res, nil
+}
+
+// storeKey hashes the parts of a key, so stores never see API keys and file
+// names stay short and safe.
+func storeKey(identity, method, key string) string {
+	sum := sha256.Sum256([]byte(identity + "\x00" + method + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+func requestHash(req proto.Message) ([]byte, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
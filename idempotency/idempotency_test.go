@@ -0,0 +1,251 @@
+package idempotency_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/fake"
+	"github.com/ferza17/grpc-course/greet/greetfake"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/idempotency"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// start serves a greetfake behind d, whose Greet answers "first" and then
+// "second" forever.
+func start(t *testing.T, d *idempotency.Deduplicator) (*greetfake.Server, greetpb.GreatServiceClient) {
+	f := greetfake.New()
+	f.Greet.
+		Enqueue(fake.Send(&greetpb.GreetResponse{Result: "first"})).
+		Enqueue(fake.Send(&greetpb.GreetResponse{Result: "second"}))
+	a, err := auth.New(config.Auth{APIKeys: []string{"alice", "bob"}})
+	if err != nil {
+		t.Fatalf("auth.New: %v", err)
+	}
+	h := harness.New(t, func(s *grpc.Server) {
+		greetpb.RegisterGreatServiceServer(s, f.Service())
+	}, grpc.ChainUnaryInterceptor(a.UnaryInterceptor, d.UnaryInterceptor))
+	return f, greetpb.NewGreatServiceClient(h.Conn)
+}
+
+func memory(t *testing.T) *idempotency.Deduplicator {
+	d, err := idempotency.New(config.Idempotency{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return d
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), idempotency.Header, key)
+}
+
+func greet(name string) *greetpb.GreatRequest {
+	return &greetpb.GreatRequest{Greeting: &greetpb.Greeting{FirstName: name}}
+}
+
+func TestDuplicateIsReplayed(t *testing.T) {
+	f, c := start(t, memory(t))
+
+	res, err := c.Greet(withKey("k1"), greet("Ann"))
+	if err != nil || res.GetResult() != "first" {
+		t.Fatalf("Greet = %v, %v; want first", res, err)
+	}
+	var header metadata.MD
+	res, err = c.Greet(withKey("k1"), greet("Ann"), grpc.Header(&header))
+	if err != nil || res.GetResult() != "first" {
+		t.Fatalf("duplicate Greet = %v, %v; want first", res, err)
+	}
+	if got := header.Get(idempotency.ReplayedHeader); len(got) != 1 || got[0] != "true" {
+		t.Errorf("%s = %v, want true", idempotency.ReplayedHeader, got)
+	}
+	if n := len(f.Greet.Calls()); n != 1 {
+		t.Errorf("handler ran %d times, want 1", n)
+	}
+
+	res, err = c.Greet(withKey("k2"), greet("Ann"))
+	if err != nil || res.GetResult() != "second" {
+		t.Errorf("Greet with another key = %v, %v; want second", res, err)
+	}
+}
+
+func TestKeyReusedForAnotherRequest(t *testing.T) {
+	_, c := start(t, memory(t))
+
+	if _, err := c.Greet(withKey("k1"), greet("Ann")); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	if _, err := c.Greet(withKey("k1"), greet("Bo")); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Greet with reused key = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestWithoutKey(t *testing.T) {
+	f, c := start(t, memory(t))
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Greet(context.Background(), greet("Ann")); err != nil {
+			t.Fatalf("Greet: %v", err)
+		}
+	}
+	if n := len(f.Greet.Calls()); n != 2 {
+		t.Errorf("handler ran %d times, want 2", n)
+	}
+}
+
+func TestCallersHaveTheirOwnKeys(t *testing.T) {
+	f, c := start(t, memory(t))
+
+	for _, apiKey := range []string{"alice", "bob"} {
+		ctx := metadata.AppendToOutgoingContext(withKey("k1"), auth.APIKeyHeader, apiKey)
+		if _, err := c.Greet(ctx, greet("Ann")); err != nil {
+			t.Fatalf("Greet as %s: %v", apiKey, err)
+		}
+	}
+	if n := len(f.Greet.Calls()); n != 2 {
+		t.Errorf("handler ran %d times, want 2", n)
+	}
+}
+
+func TestForwardedClientsHaveTheirOwnKeys(t *testing.T) {
+	f := greetfake.New()
+	f.Greet.Enqueue(fake.Send(&greetpb.GreetResponse{Result: "first"}))
+	a, err := auth.New(config.Auth{})
+	if err != nil {
+		t.Fatalf("auth.New: %v", err)
+	}
+	// Loopback peers such as the HTTP gateway are trusted to forward.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(a.UnaryInterceptor, memory(t).UnaryInterceptor))
+	greetpb.RegisterGreatServiceServer(s, f.Service())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	cc, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+
+	c := greetpb.NewGreatServiceClient(cc)
+	for _, client := range []string{"198.51.100.7", "198.51.100.8"} {
+		ctx := metadata.AppendToOutgoingContext(withKey("k1"), auth.ForwardedForHeader, client)
+		if _, err := c.Greet(ctx, greet("Ann")); err != nil {
+			t.Fatalf("Greet for %s: %v", client, err)
+		}
+	}
+	if n := len(f.Greet.Calls()); n != 2 {
+		t.Errorf("handler ran %d times, want 2", n)
+	}
+}
+
+func TestMemoryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	s := idempotency.NewMemoryStore(2)
+	e := idempotency.Entry{Expires: time.Now().Add(time.Hour)}
+	for _, key := range []string{"a", "b"} {
+		if err := s.Put(key, e); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	if _, ok, _ := s.Get("a"); !ok {
+		t.Fatal("a is missing")
+	}
+	if err := s.Put("c", e); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok, _ := s.Get(key); ok != want {
+			t.Errorf("Get(%s) found = %v, want %v", key, ok, want)
+		}
+	}
+}
+
+func TestFailureIsNotStored(t *testing.T) {
+	f, c := start(t, memory(t))
+	f.Greet.Reset()
+	f.Greet.
+		Enqueue(fake.Fail(status.Error(codes.Unavailable, "try again"))).
+		Enqueue(fake.Send(&greetpb.GreetResponse{Result: "ok"}))
+
+	if _, err := c.Greet(withKey("k1"), greet("Ann")); status.Code(err) != codes.Unavailable {
+		t.Fatalf("Greet = %v, want Unavailable", err)
+	}
+	res, err := c.Greet(withKey("k1"), greet("Ann"))
+	if err != nil || res.GetResult() != "ok" {
+		t.Errorf("retried Greet = %v, %v; want ok", res, err)
+	}
+}
+
+func TestConcurrentDuplicatesRunOnce(t *testing.T) {
+	f, c := start(t, memory(t))
+	f.Greet.Reset()
+	f.Greet.Enqueue(fake.SendAfter(50*time.Millisecond, &greetpb.GreetResponse{Result: "slow"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, err := c.Greet(withKey("k1"), greet("Ann")); err != nil || res.GetResult() != "slow" {
+				t.Errorf("Greet = %v, %v; want slow", res, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(f.Greet.Calls()); n != 1 {
+		t.Errorf("handler ran %d times, want 1", n)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	f, c := start(t, idempotency.NewWithStore(idempotency.NewMemoryStore(0), time.Millisecond))
+
+	if _, err := c.Greet(withKey("k1"), greet("Ann")); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	res, err := c.Greet(withKey("k1"), greet("Bo"))
+	if err != nil || res.GetResult() != "second" {
+		t.Errorf("Greet after expiry = %v, %v; want second", res, err)
+	}
+	if n := len(f.Greet.Calls()); n != 2 {
+		t.Errorf("handler ran %d times, want 2", n)
+	}
+}
+
+func TestFileStoreSurvivesRestart(t *testing.T) {
+	cfg := config.Idempotency{Dir: t.TempDir()}
+	d, err := idempotency.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	_, c := start(t, d)
+	if _, err := c.Greet(withKey("k1"), greet("Ann")); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+
+	restarted, err := idempotency.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	f, c := start(t, restarted)
+	res, err := c.Greet(withKey("k1"), greet("Ann"))
+	if err != nil || res.GetResult() != "first" {
+		t.Errorf("Greet after restart = %v, %v; want first", res, err)
+	}
+	if n := len(f.Greet.Calls()); n != 0 {
+		t.Errorf("handler ran %d times after restart, want 0", n)
+	}
+}
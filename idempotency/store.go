@@ -0,0 +1,198 @@
+package idempotency
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how often stores drop expired entries nobody asked for again.
+const sweepEvery = time.Minute
+
+// Entry is a stored response.
+type Entry struct {
+	// RequestHash identifies the request the response belongs to.
+	RequestHash []byte `json:"request_hash"`
+	// Type is the full name of the response message, Response its encoding.
+	Type     string `json:"type"`
+	Response []byte `json:"response"`
+	// Expires is when the entry is dropped.
+	Expires time.Time `json:"expires"`
+}
+
+// Store keeps entries until they expire. Keys are hex strings. Stores are
+// safe for concurrent use.
+type Store interface {
+	// Get returns the unexpired entry of key.
+	Get(key string) (Entry, bool, error)
+	// Put stores e under key, replacing any entry.
+	Put(key string, e Entry) error
+}
+
+// DefaultMaxEntries bounds memory stores created with a max of zero.
+const DefaultMaxEntries = 100000
+
+type memoryEntry struct {
+	key string
+	Entry
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	max       int
+	lru       *list.List // of *memoryEntry, most recently used first
+	entries   map[string]*list.Element
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns a Store that keeps up to max entries in memory,
+// DefaultMaxEntries if max is zero. When it is full the least recently used
+// entry is dropped, so a duplicate of its call runs the call again.
+func NewMemoryStore(max int) Store {
+	if max <= 0 {
+		max = DefaultMaxEntries
+	}
+	return &memoryStore{max: max, lru: list.New(), entries: map[string]*list.Element{}, lastSweep: time.Now()}
+}
+
+func (s *memoryStore) Get(key string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+	e := el.Value.(*memoryEntry)
+	if time.Now().After(e.Expires) {
+		s.remove(el)
+		return Entry{}, false, nil
+	}
+	s.lru.MoveToFront(el)
+	return e.Entry, true, nil
+}
+
+func (s *memoryStore) Put(key string, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) > sweepEvery {
+		for el := s.lru.Front(); el != nil; {
+			next := el.Next()
+			if now.After(el.Value.(*memoryEntry).Expires) {
+				s.remove(el)
+			}
+			el = next
+		}
+		s.lastSweep = now
+	}
+
+	if el, ok := s.entries[key]; ok {
+		el.Value.(*memoryEntry).Entry = e
+		s.lru.MoveToFront(el)
+		return nil
+	}
+	s.entries[key] = s.lru.PushFront(&memoryEntry{key: key, Entry: e})
+	for s.lru.Len() > s.max {
+		s.remove(s.lru.Back())
+	}
+	return nil
+}
+
+func (s *memoryStore) remove(el *list.Element) {
+	s.lru.Remove(el)
+	delete(s.entries, el.Value.(*memoryEntry).key)
+}
+
+type fileStore struct {
+	dir string
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewFileStore returns a Store that keeps each entry in a JSON file under
+// dir, creating dir if needed. Several processes may share dir.
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+func (s *fileStore) Get(key string) (Entry, bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, false, err
+	}
+	if time.Now().After(e.Expires) {
+		os.Remove(s.path(key))
+		return Entry{}, false, nil
+	}
+	return e, true, nil
+}
+
+func (s *fileStore) Put(key string, e Entry) error {
+	s.sweep()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	// Readers never see a partly written file: the entry is renamed into
+	// place once complete.
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// sweep removes expired entries at most every sweepEvery.
+func (s *fileStore) sweep() {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) < sweepEvery {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	files, _ := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		var e Entry
+		if json.Unmarshal(data, &e) == nil && now.After(e.Expires) {
+			os.Remove(name)
+		}
+	}
+}
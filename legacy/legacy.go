@@ -0,0 +1,177 @@
+// Package legacy supports serving the pre-v1 services, greet.GreatService
+// and calculator.SumService, next to their v1 successors from one
+// implementation. Their messages are wire compatible, so adapters convert
+// with Convert. A Tracker counts the calls that still arrive on the old
+// names in the expvar "legacy_calls", keyed by method and then by class of
+// caller: the hash of a verified API key, see auth.KeyHash, or else the
+// client named first in the user agent. Past maxClasses per method the
+// calls are counted as "other", so callers cannot grow the map at will:
+//
+//	"legacy_calls": {
+//	  "/greet.GreatService/Greet": {"key:sha256:5e884898": 12, "grpc-go": 3}
+//	}
+//
+// Pacing configured for a v1 method also applies to its legacy predecessor,
+// see SuccessorMethod. Rate limits are configured per method as called, so
+// the legacy names keep needing entries of their own until they are retired.
+package legacy
+
+import (
+	"context"
+	"expvar"
+	"github.com/ferza17/grpc-course/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"strings"
+	"sync"
+)
+
+// SuccessorHeader is the response header naming the service to move to.
+const SuccessorHeader = "x-successor-service"
+
+// Successors maps the legacy service names to their v1 replacements.
+var Successors = map[string]string{
+	"greet.GreatService":    "greet.v1.GreetService",
+	"calculator.SumService": "calculator.v1.CalculatorService",
+}
+
+// renamed maps the legacy methods whose successor has another name to it.
+var renamed = map[string]string{
+	"/calculator.SumService/SumData":      "Sum",
+	"/calculator.SumService/SumManyTimes": "Decompose",
+	"/calculator.SumService/AvgLongTimes": "Average",
+}
+
+// SuccessorMethod returns the v1 method that replaces the legacy full method
+// name, such as "/calculator.v1.CalculatorService/Decompose" for
+// "/calculator.SumService/SumManyTimes", and whether method is a legacy one.
+func SuccessorMethod(method string) (string, bool) {
+	i := strings.LastIndex(method, "/")
+	if i <= 0 {
+		return "", false
+	}
+	successor, ok := Successors[method[1:i]]
+	if !ok {
+		return "", false
+	}
+	name := method[i+1:]
+	if n, ok := renamed[method]; ok {
+		name = n
+	}
+	return "/" + successor + "/" + name, true
+}
+
+// maxClasses bounds the caller classes counted per method.
+const maxClasses = 100
+
+var (
+	calls   = expvar.NewMap("legacy_calls")
+	callsMu sync.Mutex // serializes adding methods to calls and classes to methods
+)
+
+// Convert copies src into dst through the wire format. Both must be wire
+// compatible versions of the same message.
+func Convert(dst, src proto.Message) error {
+	data, err := proto.Marshal(src)
+	if err != nil {
+		return status.Errorf(codes.Internal, "legacy: encode %s: %v", src.ProtoReflect().Descriptor().FullName(), err)
+	}
+	if err := proto.Unmarshal(data, dst); err != nil {
+		return status.Errorf(codes.Internal, "legacy: decode %s: %v", dst.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return nil
+}
+
+// Tracker counts calls to legacy services and tells their callers about
+// the successor in the x-successor-service response header.
+type Tracker struct {
+	// CountOnly leaves the header to the servers behind a proxy, which have
+	// trackers of their own, so callers of the proxy get it once.
+	CountOnly bool
+
+	successors map[string]string
+}
+
+// NewTracker returns a Tracker for the services in successors, a map from
+// legacy to v1 service name such as Successors.
+func NewTracker(successors map[string]string) *Tracker {
+	return &Tracker{successors: successors}
+}
+
+// track records the call if fullMethod belongs to a legacy service.
+func (t *Tracker) track(ctx context.Context, fullMethod string) {
+	service := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(service, "/"); i >= 0 {
+		service = service[:i]
+	}
+	successor, ok := t.successors[service]
+	if !ok {
+		return
+	}
+	if !t.CountOnly {
+		grpc.SetHeader(ctx, metadata.Pairs(SuccessorHeader, successor))
+	}
+	m := methodCalls(fullMethod)
+	class := callerClass(ctx)
+	if m.Get(class) == nil {
+		callsMu.Lock()
+		defer callsMu.Unlock()
+		n := 0
+		m.Do(func(expvar.KeyValue) { n++ })
+		if n >= maxClasses && m.Get(class) == nil {
+			class = "other"
+		}
+	}
+	m.Add(class, 1)
+}
+
+// callerClass names the caller without its address: the hash of its API key,
+// or the product first named in its user agent, "greet-cli" for
+// "greet-cli/1.0 grpc-go/1.79.3".
+func callerClass(ctx context.Context) string {
+	if id, ok := auth.Verified(ctx); ok {
+		if key, isKey := strings.CutPrefix(id, "key:"); isKey {
+			return "key:" + auth.KeyHash(key)
+		}
+	}
+	ua := metadata.ValueFromIncomingContext(ctx, "user-agent")
+	if len(ua) == 0 {
+		return "unknown"
+	}
+	product, _, _ := strings.Cut(strings.TrimSpace(ua[0]), " ")
+	product, _, _ = strings.Cut(product, "/")
+	if product == "" || len(product) > 32 {
+		return "unknown"
+	}
+	return product
+}
+
+// methodCalls returns the counters of fullMethod in calls.
+func methodCalls(fullMethod string) *expvar.Map {
+	if m, ok := calls.Get(fullMethod).(*expvar.Map); ok {
+		return m
+	}
+	callsMu.Lock()
+	defer callsMu.Unlock()
+	if m, ok := calls.Get(fullMethod).(*expvar.Map); ok {
+		return m
+	}
+	m := new(expvar.Map)
+	calls.Set(fullMethod, m)
+	return m
+}
+
+// UnaryInterceptor tracks unary calls.
+func (t *Tracker) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	t.track(ctx, info.FullMethod)
+	return handler(ctx, req)
+}
+
+// StreamInterceptor tracks streams.
+func (t *Tracker) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	t.track(ss.Context(), info.FullMethod)
+	return handler(srv, ss)
+}
@@ -0,0 +1,106 @@
+package legacy_test
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/legacy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"strings"
+	"testing"
+)
+
+func calls(method string) map[string]int64 {
+	got := map[string]int64{}
+	m, ok := expvar.Get("legacy_calls").(*expvar.Map).Get(method).(*expvar.Map)
+	if !ok {
+		return got
+	}
+	m.Do(func(kv expvar.KeyValue) {
+		got[kv.Key] = kv.Value.(*expvar.Int).Value()
+	})
+	return got
+}
+
+func TestTracker(t *testing.T) {
+	tracker := legacy.NewTracker(legacy.Successors)
+	a, err := auth.New(config.Auth{APIKeys: []string{"secret-key"}})
+	if err != nil {
+		t.Fatalf("auth.New: %v", err)
+	}
+	h := harness.New(t, func(s *grpc.Server) {
+		greetservice.Register(s, &greetservice.Server{})
+	}, grpc.ChainUnaryInterceptor(a.UnaryInterceptor, tracker.UnaryInterceptor))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, "secret-key")
+	var header metadata.MD
+	for i := 0; i < 2; i++ {
+		_, err := greetpb.NewGreatServiceClient(h.Conn).Greet(ctx, &greetpb.GreatRequest{}, grpc.Header(&header))
+		if err != nil {
+			t.Fatalf("legacy Greet: %v", err)
+		}
+	}
+	if got := header.Get(legacy.SuccessorHeader); len(got) != 1 || got[0] != "greet.v1.GreetService" {
+		t.Errorf("%s = %q, want greet.v1.GreetService", legacy.SuccessorHeader, got)
+	}
+	if _, err := greetv1.NewGreetServiceClient(h.Conn).Greet(ctx, &greetv1.GreetRequest{}); err != nil {
+		t.Fatalf("v1 Greet: %v", err)
+	}
+
+	got := calls("/greet.GreatService/Greet")
+	if len(got) != 1 {
+		t.Fatalf("legacy_calls = %v, want one caller", got)
+	}
+	for caller, n := range got {
+		if n != 2 {
+			t.Errorf("calls of %s = %d, want 2", caller, n)
+		}
+		if !strings.HasPrefix(caller, "key:sha256:") || strings.Contains(caller, "secret-key") || strings.Contains(caller, " ") {
+			t.Errorf("caller %q, want only a hashed API key", caller)
+		}
+	}
+	if got := calls("/greet.v1.GreetService/Greet"); len(got) != 0 {
+		t.Errorf("v1 calls were counted: %v", got)
+	}
+}
+
+func TestTrackerBoundsCallers(t *testing.T) {
+	tracker := legacy.NewTracker(legacy.Successors)
+	method := "/calculator.SumService/Sum"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	for i := 0; i < 150; i++ {
+		md := metadata.Pairs("user-agent", fmt.Sprintf("client-%d/1.0 grpc-go/1.79.3", i))
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		tracker.UnaryInterceptor(ctx, nil, info, func(context.Context, any) (any, error) { return nil, nil })
+	}
+
+	got := calls(method)
+	if len(got) != 101 {
+		t.Errorf("counted %d classes of caller, want 100 and other", len(got))
+	}
+	if got["client-0"] != 1 || got["other"] != 50 {
+		t.Errorf("client-0 = %d, other = %d; want 1 and 50", got["client-0"], got["other"])
+	}
+}
+
+func TestSuccessorMethod(t *testing.T) {
+	for method, want := range map[string]string{
+		"/greet.GreatService/GreetManyTimes":  "/greet.v1.GreetService/GreetManyTimes",
+		"/calculator.SumService/SumManyTimes": "/calculator.v1.CalculatorService/Decompose",
+		"/calculator.SumService/FindMaximum":  "/calculator.v1.CalculatorService/FindMaximum",
+	} {
+		if got, ok := legacy.SuccessorMethod(method); !ok || got != want {
+			t.Errorf("SuccessorMethod(%s) = %s, %v; want %s", method, got, ok, want)
+		}
+	}
+	if got, ok := legacy.SuccessorMethod("/greet.v1.GreetService/Greet"); ok {
+		t.Errorf("SuccessorMethod of a v1 method = %s, want none", got)
+	}
+}
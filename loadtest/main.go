@@ -0,0 +1,209 @@
+// Command loadtest drives one RPC of GreetService or CalculatorService for a
+// while and reports latency percentiles, throughput and errors by status code.
+//
+//	loadtest -concurrency 50 -duration 30s Greet
+//	loadtest -rate 200 -duration 1m -output json Sum
+//	loadtest -concurrency 100 -messages 20 GreetEveryone
+//
+// Without -rate every worker starts the next call as soon as the previous one
+// ends. With -rate calls are started on a fixed schedule by at most
+// -concurrency workers; a call due while all of them are busy is skipped and
+// counted, so the report shows when the server cannot keep up. A call is
+// measured from its start until its status is received.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ferza17/grpc-course/rpcclient"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+)
+
+var defaultAddrs = map[string]string{
+	"greet": "localhost:50051",
+	"calc":  "localhost:50052",
+}
+
+var (
+	addr        = flag.String("addr", "", "server address, defaults to the service's local port")
+	rate        = flag.Float64("rate", 0, "calls started per second, 0 runs the workers back to back")
+	concurrency = flag.Int("concurrency", 10, "calls in flight at most")
+	duration    = flag.Duration("duration", 10*time.Second, "how long to start new calls")
+	messages    = flag.Int("messages", 10, "requests sent per client or bidirectional stream")
+	callTimeout = flag.Duration("timeout", 0, "deadline of each call, 0 keeps the rpcclient default for unary calls and none for streams")
+	token       = flag.String("token", "", "bearer token sent as authorization metadata")
+	output      = flag.String("output", "text", "output format: text or json")
+)
+
+// usageError is returned for bad command lines, it exits with 2.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		var uerr usageError
+		if errors.As(err, &uerr) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) != 1 {
+		return usageError{"expected one RPC name"}
+	}
+	r, ok := rpcs[args[0]]
+	if !ok {
+		return usageError{fmt.Sprintf("unknown RPC %q", args[0])}
+	}
+	if *concurrency < 1 {
+		return usageError{"-concurrency must be at least 1"}
+	}
+	if *rate < 0 {
+		return usageError{"-rate must not be negative"}
+	}
+	if *output != "text" && *output != "json" {
+		return usageError{fmt.Sprintf("unknown output format %q", *output)}
+	}
+
+	target := defaultAddrs[r.service]
+	if *addr != "" {
+		target = *addr
+	}
+	// No method is passed as idempotent: a retry would hide the failure
+	// from the report.
+	cc, err := rpcclient.Dial(context.Background(), target, rpcclient.Options{Credentials: insecure.NewCredentials()})
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	md := metadata.MD{}
+	if *token != "" {
+		md.Append("authorization", "Bearer "+*token)
+	}
+	l := &load{
+		call: func(callCtx context.Context) (int, error) {
+			return r.call(callCtx, cc, *messages)
+		},
+		md:          md,
+		timeout:     *callTimeout,
+		concurrency: *concurrency,
+		rate:        *rate,
+	}
+	start := time.Now()
+	rec := l.run(ctx)
+	rep := rec.report(args[0], time.Since(start))
+
+	if *output == "json" {
+		return rep.writeJSON(os.Stdout)
+	}
+	rep.writeText(os.Stdout)
+	return nil
+}
+
+// load starts calls until its context is done and waits for the calls in
+// flight.
+type load struct {
+	call        func(ctx context.Context) (int, error)
+	md          metadata.MD
+	timeout     time.Duration
+	concurrency int
+	rate        float64
+}
+
+func (l *load) run(ctx context.Context) *recorder {
+	rec := newRecorder()
+	if l.rate > 0 {
+		l.paced(ctx, rec)
+	} else {
+		l.closed(ctx, rec)
+	}
+	return rec
+}
+
+// closed runs concurrency workers back to back.
+func (l *load) closed(ctx context.Context, rec *recorder) {
+	var wg sync.WaitGroup
+	for i := 0; i < l.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				l.once(rec)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// paced starts calls at rate, skipping those due while every worker is busy.
+func (l *load) paced(ctx context.Context, rec *recorder) {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / l.rate))
+	defer ticker.Stop()
+	slots := make(chan struct{}, l.concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			rec.skip()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			l.once(rec)
+		}()
+	}
+}
+
+// once makes one call. Calls are not tied to the run's context so the ones
+// in flight at the end finish and are counted.
+func (l *load) once(rec *recorder) {
+	ctx := metadata.NewOutgoingContext(context.Background(), l.md)
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	n, err := l.call(ctx)
+	rec.record(time.Since(start), n, err)
+}
+
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "Usage: %s [flags] <rpc>\n\nRPCs: %s\n\nFlags:\n", os.Args[0], strings.Join(methodNames(), ", "))
+	flag.PrintDefaults()
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/grpc/status"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// recorder collects the outcome of every call. It is safe for concurrent use.
+type recorder struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	messages  int
+	errors    map[string]int
+	skipped   int
+}
+
+func newRecorder() *recorder {
+	return &recorder{errors: map[string]int{}}
+}
+
+func (r *recorder) record(latency time.Duration, messages int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies = append(r.latencies, latency)
+	r.messages += messages
+	if err != nil {
+		r.errors[status.Code(err).String()]++
+	}
+}
+
+// skip counts a call the rate schedule wanted to start while every worker
+// was busy.
+func (r *recorder) skip() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.skipped++
+}
+
+// Report is the summary of a run, written as text or JSON.
+type Report struct {
+	RPC         string         `json:"rpc"`
+	Duration    Duration       `json:"duration"`
+	Calls       int            `json:"calls"`
+	Succeeded   int            `json:"succeeded"`
+	Failed      int            `json:"failed"`
+	Skipped     int            `json:"skipped"`
+	Messages    int            `json:"messages"`
+	CallsPerSec float64        `json:"callsPerSec"`
+	MsgsPerSec  float64        `json:"messagesPerSec"`
+	Latency     Latency        `json:"latency"`
+	Errors      map[string]int `json:"errors"`
+}
+
+// Latency holds the latency distribution of all calls, failed ones included.
+type Latency struct {
+	Min  Duration `json:"min"`
+	Mean Duration `json:"mean"`
+	P50  Duration `json:"p50"`
+	P90  Duration `json:"p90"`
+	P95  Duration `json:"p95"`
+	P99  Duration `json:"p99"`
+	Max  Duration `json:"max"`
+}
+
+// Duration is written to JSON as a string such as "1.5ms".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (r *recorder) report(rpc string, elapsed time.Duration) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rep := Report{
+		RPC:      rpc,
+		Duration: Duration(elapsed),
+		Calls:    len(r.latencies),
+		Skipped:  r.skipped,
+		Messages: r.messages,
+		Errors:   map[string]int{},
+	}
+	for code, n := range r.errors {
+		rep.Errors[code] = n
+		rep.Failed += n
+	}
+	rep.Succeeded = rep.Calls - rep.Failed
+	if secs := elapsed.Seconds(); secs > 0 {
+		rep.CallsPerSec = float64(rep.Calls) / secs
+		rep.MsgsPerSec = float64(rep.Messages) / secs
+	}
+
+	if len(r.latencies) == 0 {
+		return rep
+	}
+	sorted := append([]time.Duration(nil), r.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	rep.Latency = Latency{
+		Min:  Duration(sorted[0]),
+		Mean: Duration(sum / time.Duration(len(sorted))),
+		P50:  Duration(percentile(sorted, 50)),
+		P90:  Duration(percentile(sorted, 90)),
+		P95:  Duration(percentile(sorted, 95)),
+		P99:  Duration(percentile(sorted, 99)),
+		Max:  Duration(sorted[len(sorted)-1]),
+	}
+	return rep
+}
+
+// percentile returns the nearest-rank percentile p of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func (rep Report) writeText(w io.Writer) {
+	fmt.Fprintf(w, "RPC:         %s\n", rep.RPC)
+	fmt.Fprintf(w, "Duration:    %s\n", rep.Duration)
+	fmt.Fprintf(w, "Calls:       %d (%d ok, %d failed, %d skipped)\n", rep.Calls, rep.Succeeded, rep.Failed, rep.Skipped)
+	fmt.Fprintf(w, "Throughput:  %.1f calls/s, %.1f messages/s\n", rep.CallsPerSec, rep.MsgsPerSec)
+	l := rep.Latency
+	fmt.Fprintf(w, "Latency:     min %s, mean %s, max %s\n", l.Min, l.Mean, l.Max)
+	fmt.Fprintf(w, "             p50 %s, p90 %s, p95 %s, p99 %s\n", l.P50, l.P90, l.P95, l.P99)
+	if len(rep.Errors) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Errors:")
+	var codes []string
+	for code := range rep.Errors {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "  %-18s %d\n", code, rep.Errors[code])
+	}
+}
+
+func (rep Report) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
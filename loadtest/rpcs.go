@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"google.golang.org/grpc"
+	"io"
+)
+
+// rpc drives one call and returns the number of messages it received.
+type rpc struct {
+	service string
+	call    func(ctx context.Context, cc grpc.ClientConnInterface, messages int) (int, error)
+}
+
+// rpcs holds the eight RPCs by method name. Streaming inputs send messages
+// requests per call.
+var rpcs = map[string]rpc{
+	"Greet":          {service: "greet", call: greet},
+	"GreetManyTimes": {service: "greet", call: greetManyTimes},
+	"LongGreet":      {service: "greet", call: longGreet},
+	"GreetEveryone":  {service: "greet", call: greetEveryone},
+	"Sum":            {service: "calc", call: sum},
+	"Decompose":      {service: "calc", call: decompose},
+	"Average":        {service: "calc", call: average},
+	"FindMaximum":    {service: "calc", call: findMaximum},
+}
+
+var greeting = &greetv1.Greeting{FirstName: "Load", LastName: "Test"}
+
+func greet(ctx context.Context, cc grpc.ClientConnInterface, _ int) (int, error) {
+	_, err := greetv1.NewGreetServiceClient(cc).Greet(ctx, &greetv1.GreetRequest{Greeting: greeting})
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func greetManyTimes(ctx context.Context, cc grpc.ClientConnInterface, _ int) (int, error) {
+	stream, err := greetv1.NewGreetServiceClient(cc).GreetManyTimes(ctx, &greetv1.GreetManyTimesRequest{Greeting: greeting})
+	if err != nil {
+		return 0, err
+	}
+	return drain(func() error {
+		_, err := stream.Recv()
+		return err
+	})
+}
+
+func longGreet(ctx context.Context, cc grpc.ClientConnInterface, messages int) (int, error) {
+	stream, err := greetv1.NewGreetServiceClient(cc).LongGreet(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < messages; i++ {
+		if err := stream.Send(&greetv1.LongGreetRequest{Greeting: greeting}); err != nil {
+			break // the status is returned by CloseAndRecv
+		}
+	}
+	if _, err := stream.CloseAndRecv(); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func greetEveryone(ctx context.Context, cc grpc.ClientConnInterface, messages int) (int, error) {
+	stream, err := greetv1.NewGreetServiceClient(cc).GreetEveryone(ctx)
+	if err != nil {
+		return 0, err
+	}
+	go func() {
+		for i := 0; i < messages; i++ {
+			if err := stream.Send(&greetv1.GreetEveryoneRequest{Greeting: greeting}); err != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+	return drain(func() error {
+		_, err := stream.Recv()
+		return err
+	})
+}
+
+func sum(ctx context.Context, cc grpc.ClientConnInterface, _ int) (int, error) {
+	_, err := calculatorv1.NewCalculatorServiceClient(cc).Sum(ctx, &calculatorv1.SumRequest{Operands: &calculatorv1.Operands{A: 3, B: 10}})
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func decompose(ctx context.Context, cc grpc.ClientConnInterface, _ int) (int, error) {
+	stream, err := calculatorv1.NewCalculatorServiceClient(cc).Decompose(ctx, &calculatorv1.DecomposeRequest{Number: 120})
+	if err != nil {
+		return 0, err
+	}
+	return drain(func() error {
+		_, err := stream.Recv()
+		return err
+	})
+}
+
+func average(ctx context.Context, cc grpc.ClientConnInterface, messages int) (int, error) {
+	stream, err := calculatorv1.NewCalculatorServiceClient(cc).Average(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < messages; i++ {
+		if err := stream.Send(&calculatorv1.AverageRequest{Number: int32(i)}); err != nil {
+			break // the status is returned by CloseAndRecv
+		}
+	}
+	if _, err := stream.CloseAndRecv(); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func findMaximum(ctx context.Context, cc grpc.ClientConnInterface, messages int) (int, error) {
+	stream, err := calculatorv1.NewCalculatorServiceClient(cc).FindMaximum(ctx)
+	if err != nil {
+		return 0, err
+	}
+	go func() {
+		for i := 0; i < messages; i++ {
+			if err := stream.Send(&calculatorv1.FindMaximumRequest{Number: int32(i)}); err != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+	return drain(func() error {
+		_, err := stream.Recv()
+		return err
+	})
+}
+
+// drain calls recv until the stream ends and counts the messages.
+func drain(recv func() error) (int, error) {
+	n := 0
+	for {
+		err := recv()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// methodNames lists the RPCs for usage messages.
+func methodNames() []string {
+	return []string{
+		"Greet", "GreetManyTimes", "LongGreet", "GreetEveryone",
+		"Sum", "Decompose", "Average", "FindMaximum",
+	}
+}
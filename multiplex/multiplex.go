@@ -0,0 +1,184 @@
+// Package multiplex runs the gRPC server and the HTTP handler of a process,
+// on a listener each or together on one, and shuts both down gracefully.
+//
+// On a single listener every connection is served by net/http, over
+// HTTP/1.1, HTTP/2 over TLS when the listener negotiates it through ALPN, or
+// HTTP/2 without TLS (h2c, with prior knowledge, as gRPC clients dial
+// plaintext servers). HTTP/2 requests with a gRPC content-type go to the gRPC
+// server, everything else, gRPC-Web included, to the HTTP handler. gRPC is
+// then served by grpc.Server.ServeHTTP, which does not apply the keepalive
+// settings of package transport.
+package multiplex
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultGrace is used when Server.Grace is zero.
+const DefaultGrace = 10 * time.Second
+
+// Server is the gRPC server of a process and its HTTP handler.
+type Server struct {
+	GRPC *grpc.Server
+	// HTTP serves the requests that are not gRPC; nil serves gRPC only.
+	HTTP http.Handler
+	// Health, when set, reports NOT_SERVING from the start of the shutdown
+	// so load balancers stop sending calls while those in flight finish.
+	Health *health.Server
+	// Grace is how long calls in flight may take to finish on shutdown
+	// before they are cut.
+	Grace time.Duration
+}
+
+// IsGRPC reports whether r is a gRPC call. gRPC-Web calls are not, they are
+// translated by package web.
+func IsGRPC(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	return r.ProtoMajor == 2 && (contentType == "application/grpc" ||
+		strings.HasPrefix(contentType, "application/grpc+") ||
+		strings.HasPrefix(contentType, "application/grpc;"))
+}
+
+// Handler passes gRPC calls to s.GRPC and other requests to s.HTTP.
+func (s *Server) Handler() http.Handler {
+	next := s.HTTP
+	if next == nil {
+		next = http.NotFoundHandler()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsGRPC(r) {
+			s.GRPC.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// httpServer returns a server for h speaking HTTP/1.1, HTTP/2 and h2c.
+func httpServer(h http.Handler) *http.Server {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Server{Handler: h, Protocols: protocols}
+}
+
+// Serve serves gRPC on grpcLis and HTTP on httpLis, which is nil when
+// s.HTTP is. It returns when ctx is done and the servers have shut down, or
+// when either fails.
+func (s *Server) Serve(ctx context.Context, grpcLis, httpLis net.Listener) error {
+	var hs *http.Server
+	if httpLis != nil {
+		hs = httpServer(s.HTTP)
+	}
+	errs := make(chan error, 2)
+	go func() { errs <- s.GRPC.Serve(grpcLis) }()
+	if hs != nil {
+		go func() { errs <- hs.Serve(httpLis) }()
+	}
+
+	select {
+	case err := <-errs:
+		s.GRPC.Stop()
+		if hs != nil {
+			hs.Close()
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	if s.Health != nil {
+		s.Health.Shutdown()
+	}
+	grace, cancel := context.WithTimeout(context.Background(), s.grace())
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		stopGRPC(grace, s.GRPC)
+	}()
+	if hs != nil {
+		shutdownHTTP(grace, hs)
+	}
+	wg.Wait()
+	return nil
+}
+
+// ServeSingle serves gRPC and HTTP on lis. It returns when ctx is done and
+// the servers have shut down, or when serving fails.
+func (s *Server) ServeSingle(ctx context.Context, lis net.Listener) error {
+	hs := httpServer(s.Handler())
+	errs := make(chan error, 1)
+	go func() { errs <- hs.Serve(lis) }()
+
+	select {
+	case err := <-errs:
+		hs.Close()
+		s.GRPC.Stop()
+		return err
+	case <-ctx.Done():
+	}
+
+	if s.Health != nil {
+		s.Health.Shutdown()
+	}
+	grace, cancel := context.WithTimeout(context.Background(), s.grace())
+	defer cancel()
+	// The gRPC calls are HTTP/2 requests here, so shutting down the HTTP
+	// server waits for them. GracefulStop is not supported by ServeHTTP.
+	shutdownHTTP(grace, hs)
+	s.GRPC.Stop()
+	return nil
+}
+
+func (s *Server) grace() time.Duration {
+	if s.Grace > 0 {
+		return s.Grace
+	}
+	return DefaultGrace
+}
+
+// stopGRPC lets the calls in flight finish until ctx is done, then cuts them.
+func stopGRPC(ctx context.Context, s *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
+
+// shutdownHTTP lets the requests in flight finish until ctx is done, then
+// closes their connections.
+func shutdownHTTP(ctx context.Context, hs *http.Server) {
+	if err := hs.Shutdown(ctx); errors.Is(err, context.DeadlineExceeded) {
+		hs.Close()
+	}
+}
+
+// Healthz answers 200 while hs reports the server as serving and 503
+// otherwise, for probes that speak HTTP only.
+func Healthz(hs *health.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, err := hs.Check(r.Context(), &healthpb.HealthCheckRequest{})
+		if err != nil || res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			http.Error(w, "NOT_SERVING", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("SERVING\n"))
+	})
+}
@@ -0,0 +1,197 @@
+package multiplex_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/multiplex"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIsGRPC(t *testing.T) {
+	for _, tt := range []struct {
+		proto       int
+		contentType string
+		want        bool
+	}{
+		{2, "application/grpc", true},
+		{2, "application/grpc+proto", true},
+		{2, "application/grpc;charset=utf-8", true},
+		{2, "application/grpc-web", false},
+		{2, "application/grpc-web-text", false},
+		{2, "application/json", false},
+		{1, "application/grpc", false},
+	} {
+		r := &http.Request{ProtoMajor: tt.proto, Header: http.Header{"Content-Type": {tt.contentType}}}
+		if got := multiplex.IsGRPC(r); got != tt.want {
+			t.Errorf("IsGRPC(HTTP/%d %s) = %v, want %v", tt.proto, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+// newServer returns a Server of GreetService with /hello over HTTP.
+func newServer() *multiplex.Server {
+	s := grpc.NewServer()
+	greetservice.Register(s, &greetservice.Server{Out: io.Discard})
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+	mux.Handle("/healthz", multiplex.Healthz(hs))
+	return &multiplex.Server{GRPC: s, HTTP: mux, Health: hs, Grace: time.Second}
+}
+
+func listen(t *testing.T) net.Listener {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	return lis
+}
+
+func dial(t *testing.T, addr string) greetv1.GreetServiceClient {
+	cc, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return greetv1.NewGreetServiceClient(cc)
+}
+
+// get returns the status and body of url fetched with c.
+func get(t *testing.T, c *http.Client, url string) (int, string) {
+	t.Helper()
+	res, err := c.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	return res.StatusCode, string(body)
+}
+
+// h2c is a client speaking HTTP/2 without TLS.
+func h2c() *http.Client {
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Client{Transport: &http.Transport{Protocols: protocols}}
+}
+
+func TestServeSingle(t *testing.T) {
+	srv := newServer()
+	lis := listen(t)
+	addr := lis.Addr().String()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.ServeSingle(ctx, lis) }()
+
+	c := dial(t, addr)
+	res, err := c.Greet(context.Background(), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Ann"}})
+	if err != nil || res.GetResult() != "Hello Ann" {
+		t.Errorf("Greet = %v, %v; want Hello Ann", res, err)
+	}
+	if code, body := get(t, http.DefaultClient, "http://"+addr+"/hello"); code != http.StatusOK || body != "HTTP/1.1" {
+		t.Errorf("HTTP/1.1 GET = %d %q, want 200 HTTP/1.1", code, body)
+	}
+	if code, body := get(t, h2c(), "http://"+addr+"/hello"); code != http.StatusOK || body != "HTTP/2.0" {
+		t.Errorf("h2c GET = %d %q, want 200 HTTP/2.0", code, body)
+	}
+	if code, _ := get(t, http.DefaultClient, "http://"+addr+"/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz = %d, want 200", code)
+	}
+
+	// A stream open when the shutdown starts may finish.
+	stream, err := c.GreetEveryone(context.Background())
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	send := func(name string) error {
+		if err := stream.Send(&greetv1.GreetEveryoneRequest{Greeting: &greetv1.Greeting{FirstName: name}}); err != nil {
+			return err
+		}
+		_, err := stream.Recv()
+		return err
+	}
+	if err := send("Bo"); err != nil {
+		t.Fatalf("GreetEveryone before shutdown: %v", err)
+	}
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	if err := send("Cy"); err != nil {
+		t.Errorf("GreetEveryone during shutdown: %v", err)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("ServeSingle returned %v with a stream open", err)
+	default:
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("ServeSingle = %v", err)
+	}
+	if res, _ := srv.Health.Check(context.Background(), &healthpb.HealthCheckRequest{}); res.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("health after shutdown = %v, want NOT_SERVING", res.GetStatus())
+	}
+}
+
+func TestServe(t *testing.T) {
+	srv := newServer()
+	grpcLis, httpLis := listen(t), listen(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, grpcLis, httpLis) }()
+
+	if _, err := dial(t, grpcLis.Addr().String()).Greet(context.Background(), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Ann"}}); err != nil {
+		t.Errorf("Greet: %v", err)
+	}
+	if code, _ := get(t, http.DefaultClient, "http://"+httpLis.Addr().String()+"/hello"); code != http.StatusOK {
+		t.Errorf("GET = %d, want 200", code)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Serve = %v", err)
+	}
+}
+
+func TestGraceCutsStreams(t *testing.T) {
+	srv := newServer()
+	srv.Grace = 50 * time.Millisecond
+	lis := listen(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.ServeSingle(ctx, lis) }()
+
+	stream, err := dial(t, lis.Addr().String()).GreetEveryone(context.Background())
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	if err := stream.Send(&greetv1.GreetEveryoneRequest{Greeting: &greetv1.Greeting{FirstName: "Bo"}}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeSingle did not return after the grace period")
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("stream still open after the grace period")
+	}
+}
@@ -0,0 +1,180 @@
+// Package pacing spaces the messages handlers send on server streams. The
+// services used to sleep a second per message for the sake of the demo; the
+// pause is now a per-method policy, none by default, that the server sets in
+// its config and callers can adjust within the bounds it allows:
+//
+//	"pacing": {
+//	  "methods": {
+//	    "/greet.v1.GreetService/GreetManyTimes": {
+//	      "mode": "fixed", "interval": "1s", "max_interval": "5s"
+//	    }
+//	  }
+//	}
+//
+// The legacy methods are paced like their v1 successors, see
+// legacy.SuccessorMethod, unless they have settings of their own.
+//
+// A caller asks for its own interval with the pace-interval header, such as
+// "250ms", and gets fixed pacing at that interval clamped to the method's
+// bounds.
+package pacing
+
+import (
+	"context"
+	"fmt"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/deadline"
+	"github.com/ferza17/grpc-course/legacy"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+// IntervalHeader is the request header asking for a fixed interval.
+const IntervalHeader = "pace-interval"
+
+// Modes of config.Pace.
+const (
+	None        = "none"
+	Fixed       = "fixed"
+	TokenBucket = "token_bucket"
+)
+
+// Validate reports settings New would not understand.
+func Validate(cfg config.Pacing) error {
+	if err := validate(cfg.Default); err != nil {
+		return fmt.Errorf("default pacing: %w", err)
+	}
+	for method, pace := range cfg.Methods {
+		if err := validate(pace); err != nil {
+			return fmt.Errorf("pacing of %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+func validate(pace config.Pace) error {
+	switch pace.Mode {
+	case "", None, Fixed:
+	case TokenBucket:
+		if pace.Rate <= 0 {
+			return fmt.Errorf("token_bucket needs a positive rate")
+		}
+	default:
+		return fmt.Errorf("unknown mode %q", pace.Mode)
+	}
+	if pace.MaxInterval > 0 && pace.MinInterval > pace.MaxInterval {
+		return fmt.Errorf("min_interval %v is above max_interval %v", pace.MinInterval, pace.MaxInterval)
+	}
+	return nil
+}
+
+// Policy picks the pacing of each call. Its settings can be replaced with
+// Update while the server runs; calls in progress keep their pacing. A nil
+// Policy paces nothing.
+type Policy struct {
+	mu  sync.RWMutex
+	cfg config.Pacing
+}
+
+// New returns a Policy for cfg, which should have passed Validate.
+func New(cfg config.Pacing) *Policy {
+	return &Policy{cfg: cfg}
+}
+
+// Update replaces the settings.
+func (p *Policy) Update(cfg config.Pacing) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = cfg
+}
+
+func (p *Policy) pace(method string) config.Pace {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if pace, ok := p.cfg.Methods[method]; ok {
+		return pace
+	}
+	if successor, ok := legacy.SuccessorMethod(method); ok {
+		if pace, ok := p.cfg.Methods[successor]; ok {
+			return pace
+		}
+	}
+	return p.cfg.Default
+}
+
+// Pacer returns the pacer of the call with context ctx, a server handler
+// context. An unreadable pace-interval header is an InvalidArgument error.
+func (p *Policy) Pacer(ctx context.Context) (*Pacer, error) {
+	if p == nil {
+		return &Pacer{}, nil
+	}
+	method, _ := grpc.Method(ctx)
+	pace := p.pace(method)
+
+	if pace.MaxInterval > 0 {
+		if v := metadata.ValueFromIncomingContext(ctx, IntervalHeader); len(v) > 0 {
+			interval, err := time.ParseDuration(v[0])
+			if err != nil || interval < 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "%s %q is not a duration such as 250ms", IntervalHeader, v[0])
+			}
+			return &Pacer{interval: clamp(interval, time.Duration(pace.MinInterval), time.Duration(pace.MaxInterval))}, nil
+		}
+	}
+
+	switch pace.Mode {
+	case Fixed:
+		return &Pacer{interval: time.Duration(pace.Interval)}, nil
+	case TokenBucket:
+		burst := pace.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		return &Pacer{limiter: rate.NewLimiter(rate.Limit(pace.Rate), burst)}, nil
+	}
+	return &Pacer{}, nil
+}
+
+func clamp(d, min, max time.Duration) time.Duration {
+	if d < min {
+		return min
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// Pacer spaces the messages of one call. It is not safe for concurrent use.
+type Pacer struct {
+	interval time.Duration
+	limiter  *rate.Limiter
+	started  bool
+}
+
+// Wait blocks until the next message may be sent, or until ctx is done, in
+// which case it returns the matching status error. Call it before every
+// message; the first one never waits under fixed pacing.
+func (p *Pacer) Wait(ctx context.Context) error {
+	if p.limiter != nil {
+		r := p.limiter.Reserve()
+		if err := deadline.Sleep(ctx, r.Delay()); err != nil {
+			r.Cancel()
+			return err
+		}
+		return nil
+	}
+
+	if !p.started {
+		p.started = true
+		return deadline.Err(ctx)
+	}
+	if p.interval <= 0 {
+		return deadline.Err(ctx)
+	}
+	return deadline.Sleep(ctx, p.interval)
+}
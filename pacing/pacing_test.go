@@ -0,0 +1,134 @@
+package pacing_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/pacing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+	"time"
+)
+
+const method = "/greet.v1.GreetService/GreetManyTimes"
+
+// streamTime returns how long GreetManyTimes takes to send its ten messages
+// under cfg, with the pace-interval header set to interval unless empty.
+func streamTime(t *testing.T, cfg config.Pacing, interval string) (time.Duration, error) {
+	t.Helper()
+	h := harness.New(t, func(s *grpc.Server) {
+		greetservice.Register(s, greetservice.New(pacing.New(cfg)))
+	})
+	c := greetv1.NewGreetServiceClient(h.Conn)
+
+	ctx := context.Background()
+	if interval != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, pacing.IntervalHeader, interval)
+	}
+	start := time.Now()
+	stream, err := c.GreetManyTimes(ctx, &greetv1.GreetManyTimesRequest{})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			return time.Since(start), nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func fixed(interval, min, max time.Duration) config.Pacing {
+	return config.Pacing{Methods: map[string]config.Pace{method: {
+		Mode:        pacing.Fixed,
+		Interval:    config.Duration(interval),
+		MinInterval: config.Duration(min),
+		MaxInterval: config.Duration(max),
+	}}}
+}
+
+func TestPacing(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.Pacing
+		interval string
+		min, max time.Duration
+	}{
+		{name: "none", cfg: config.Pacing{}, max: 50 * time.Millisecond},
+		{name: "fixed", cfg: fixed(20*time.Millisecond, 0, 0), min: 180 * time.Millisecond, max: time.Second},
+		{name: "other method", cfg: config.Pacing{Methods: map[string]config.Pace{
+			"/greet.v1.GreetService/Greet": {Mode: pacing.Fixed, Interval: config.Duration(time.Hour)},
+		}}, max: 50 * time.Millisecond},
+		{name: "token bucket", cfg: config.Pacing{Default: config.Pace{Mode: pacing.TokenBucket, Rate: 100, Burst: 5}},
+			min: 40 * time.Millisecond, max: time.Second},
+		{name: "override ignored", cfg: fixed(0, 0, 0), interval: "1h", max: 50 * time.Millisecond},
+		{name: "override", cfg: fixed(time.Hour, 0, time.Hour), interval: "0s", max: 50 * time.Millisecond},
+		{name: "override clamped to min", cfg: fixed(0, 20*time.Millisecond, time.Hour), interval: "0s",
+			min: 180 * time.Millisecond, max: time.Second},
+		{name: "override clamped to max", cfg: fixed(0, 0, 20*time.Millisecond), interval: "1h",
+			min: 180 * time.Millisecond, max: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := pacing.Validate(tt.cfg); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			took, err := streamTime(t, tt.cfg, tt.interval)
+			if err != nil {
+				t.Fatalf("Recv: %v", err)
+			}
+			if took < tt.min || took > tt.max {
+				t.Errorf("stream took %v, want between %v and %v", took, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestLegacyMethodsFollowTheirSuccessor(t *testing.T) {
+	h := harness.New(t, func(s *grpc.Server) {
+		greetservice.Register(s, greetservice.New(pacing.New(fixed(20*time.Millisecond, 0, 0))))
+	})
+	start := time.Now()
+	stream, err := greetpb.NewGreatServiceClient(h.Conn).GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+	}
+	if d := time.Since(start); d < 180*time.Millisecond {
+		t.Errorf("legacy GreetManyTimes took %v, want the 20ms pacing of its successor", d)
+	}
+}
+
+func TestBadInterval(t *testing.T) {
+	_, err := streamTime(t, fixed(0, 0, time.Second), "soon")
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Recv error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []config.Pace{
+		{Mode: "sleepy"},
+		{Mode: pacing.TokenBucket},
+		{Mode: pacing.Fixed, MinInterval: config.Duration(time.Second), MaxInterval: config.Duration(time.Millisecond)},
+	}
+	for _, pace := range bad {
+		if err := pacing.Validate(config.Pacing{Methods: map[string]config.Pace{method: pace}}); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", pace)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package proxy forwards gRPC calls of any method to the backend serving
+// their service, without decoding their messages, see package rawcodec. All
+// four kinds of method are forwarded as bidirectional streams, so server
+// interceptors see every proxied call as a stream of *[]byte messages:
+//
+//	r, err := proxy.NewRouter(map[string]string{"greet": "localhost:50051"}, dialOpts...)
+//	s := grpc.NewServer(
+//		grpc.UnknownServiceHandler(r.Handler),
+//		grpc.ForceServerCodec(rawcodec.Codec{}),
+//	)
+package proxy
+
+import (
+	"context"
+	"errors"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/rawcodec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Router holds a connection per backend and the routes to them.
+type Router struct {
+	// routes are sorted by length, the longest first.
+	routes []string
+	conns  map[string]*grpc.ClientConn
+}
+
+// NewRouter returns a Router for backends, keyed by full service name,
+// "greet.v1.GreetService", or by package prefix, "greet" or "greet.v1". A
+// call goes to the longest key matching its service. Connections are made
+// with opts when first used.
+func NewRouter(backends map[string]string, opts ...grpc.DialOption) (*Router, error) {
+	r := &Router{conns: map[string]*grpc.ClientConn{}}
+	byAddr := map[string]*grpc.ClientConn{}
+	for route, addr := range backends {
+		cc, ok := byAddr[addr]
+		if !ok {
+			var err error
+			cc, err = grpc.NewClient(addr, opts...)
+			if err != nil {
+				r.Close()
+				return nil, err
+			}
+			byAddr[addr] = cc
+		}
+		r.routes = append(r.routes, route)
+		r.conns[route] = cc
+	}
+	sort.Slice(r.routes, func(i, j int) bool {
+		return len(r.routes[i]) > len(r.routes[j])
+	})
+	return r, nil
+}
+
+// Close closes the connections to the backends.
+func (r *Router) Close() error {
+	closed := map[*grpc.ClientConn]bool{}
+	var errs []error
+	for _, cc := range r.conns {
+		if !closed[cc] {
+			closed[cc] = true
+			errs = append(errs, cc.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Backend returns the connection to the backend serving fullMethod.
+func (r *Router) Backend(fullMethod string) (*grpc.ClientConn, bool) {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	for _, route := range r.routes {
+		if service == route || strings.HasPrefix(service, route+".") {
+			return r.conns[route], true
+		}
+	}
+	return nil, false
+}
+
+// Handler forwards a call to its backend. Calls of services without a
+// backend fail with Unimplemented, as they would on a server not serving
+// them.
+func (r *Router) Handler(_ any, ss grpc.ServerStream) error {
+	method, ok := grpc.MethodFromServerStream(ss)
+	if !ok {
+		return status.Error(codes.Internal, "proxy: no method in stream")
+	}
+	cc, ok := r.Backend(method)
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown service %s", strings.Split(strings.TrimPrefix(method, "/"), "/")[0])
+	}
+	return Forward(ss, cc, method)
+}
+
+// omitted are the transport headers, set anew on every hop.
+var omitted = map[string]bool{
+	"content-type":         true,
+	"grpc-accept-encoding": true,
+	"user-agent":           true,
+}
+
+func forwarded(md metadata.MD) metadata.MD {
+	out := metadata.MD{}
+	for key, values := range md {
+		if !omitted[key] && !strings.HasPrefix(key, ":") {
+			out[key] = values
+		}
+	}
+	return out
+}
+
+// Forward sends the call on ss to method on cc and relays the messages, the
+// header, the trailer and the status back. The deadline and the request
+// metadata are passed on; cancelling the call cancels it on the backend. The
+// backend learns the client address from x-forwarded-for, which it believes
+// if the proxy is among its config.Auth.TrustedProxies.
+func Forward(ss grpc.ServerStream, cc grpc.ClientConnInterface, method string) error {
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	out := forwarded(md)
+	if addr := auth.ClientAddr(ss.Context()); addr != "" {
+		out.Set(auth.ForwardedForHeader, addr)
+	} else {
+		delete(out, auth.ForwardedForHeader)
+	}
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ss.Context(), out))
+	defer cancel()
+	desc := &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}
+	cs, err := cc.NewStream(ctx, desc, method, grpc.ForceCodec(rawcodec.Codec{}))
+	if err != nil {
+		return err
+	}
+
+	// Requests are forwarded until the client closes its side. A failed send
+	// means the call is over; its status is returned by RecvMsg below.
+	go func() {
+		for {
+			var msg []byte
+			if err := ss.RecvMsg(&msg); err != nil {
+				if err == io.EOF {
+					cs.CloseSend()
+				}
+				return
+			}
+			if err := cs.SendMsg(&msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	for first := true; ; first = false {
+		var msg []byte
+		err := cs.RecvMsg(&msg)
+		if first {
+			// The header has arrived with the first message or the status.
+			header, _ := cs.Header()
+			ss.SetHeader(forwarded(header))
+		}
+		if err != nil {
+			ss.SetTrailer(forwarded(cs.Trailer()))
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := ss.SendMsg(&msg); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Command proxy_server accepts the calls of every service on one port and
+// forwards them to the backends configured in config.Backends, by default
+// greet_server and calculator_server on their local ports. Credentials are
+// checked, calls to legacy services counted, recorded by the audit log and
+// rate limited here, once, at the edge. Credentials are passed on to the
+// backends, and the client address in x-forwarded-for: list the proxy's
+// address in the backends' auth.trusted_proxies so they account calls to
+// the client and not to the proxy.
+package main
+
+import (
+	"context"
+	"expvar"
+	"flag"
+	"fmt"
+	"github.com/ferza17/grpc-course/audit"
+	"github.com/ferza17/grpc-course/auth"
+	// Register the message types the audit log decodes.
+	_ "github.com/ferza17/grpc-course/calculator/calculatorpb"
+	_ "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/config"
+	_ "github.com/ferza17/grpc-course/greet/greetpb"
+	_ "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/legacy"
+	"github.com/ferza17/grpc-course/multiplex"
+	"github.com/ferza17/grpc-course/proxy"
+	"github.com/ferza17/grpc-course/ratelimit"
+	"github.com/ferza17/grpc-course/rawcodec"
+	"github.com/ferza17/grpc-course/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var configPath = flag.String("config", "", "path to a JSON config file")
+
+// defaultBackends are used when the config has none. They are not part of the
+// defaults passed to config.Load, which would merge the configured ones into
+// them.
+var defaultBackends = map[string]string{
+	"greet":      "localhost:50051",
+	"calculator": "localhost:50052",
+}
+
+func main() {
+	flag.Parse()
+	defaults := config.Config{
+		Addr:      "0.0.0.0:50050",
+		HTTPAddr:  "0.0.0.0:8080",
+		Transport: config.Transport{Keepalive: transport.DefaultKeepalive},
+	}
+	cfg, err := config.Load(*configPath, defaults)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if len(cfg.Backends) == 0 {
+		cfg.Backends = defaultBackends
+	}
+
+	fmt.Println("Proxy about to start...")
+	lis, err := transport.Listen(cfg.Addr, cfg.Transport)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+
+	router, err := proxy.NewRouter(cfg.Backends, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to dial backends: %v", err)
+	}
+	defer router.Close()
+	authenticator, err := auth.New(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+	tracker := legacy.NewTracker(legacy.Successors)
+	tracker.CountOnly = true
+	limiter := ratelimit.New(cfg.RateLimit)
+	auditor, err := audit.New(cfg.Audit)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer auditor.Close()
+	go config.Watch(*configPath, defaults, 5*time.Second, nil, func(cfg config.Config) {
+		log.Println("Reloading rate limits")
+		limiter.Update(cfg.RateLimit)
+	})
+
+	// Proxied calls are streams whatever their kind, so only the stream
+	// interceptors apply to them.
+	opts := append(transport.ServerOptions(cfg.Transport),
+		grpc.UnknownServiceHandler(router.Handler),
+		grpc.ForceServerCodec(rawcodec.Codec{}),
+		grpc.ChainStreamInterceptor(
+			authenticator.StreamInterceptor,
+			tracker.StreamInterceptor,
+			auditor.StreamInterceptor,
+			limiter.StreamInterceptor,
+		),
+	)
+	s := grpc.NewServer(opts...)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+
+	srv := &multiplex.Server{GRPC: s, Health: healthServer, Grace: time.Duration(cfg.ShutdownGrace)}
+	if cfg.HTTPAddr != "" || cfg.SinglePort {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		// There is no Prometheus exporter yet, /metrics is the expvar JSON.
+		mux.Handle("/metrics", expvar.Handler())
+		mux.Handle("/healthz", multiplex.Healthz(healthServer))
+		srv.HTTP = mux
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if cfg.SinglePort {
+		err = srv.ServeSingle(ctx, lis)
+	} else {
+		var httpLis net.Listener
+		if srv.HTTP != nil {
+			if httpLis, err = transport.Listen(cfg.HTTPAddr, cfg.Transport); err != nil {
+				log.Fatalf("Failed to listen for HTTP: %v", err)
+			}
+		}
+		err = srv.Serve(ctx, lis, httpLis)
+	}
+	if err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+	log.Println("Proxy stopped")
+}
@@ -0,0 +1,227 @@
+package proxy_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/calculator/calcservice"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/legacy"
+	"github.com/ferza17/grpc-course/proxy"
+	"github.com/ferza17/grpc-course/rawcodec"
+	"github.com/ferza17/grpc-course/validate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestBackend(t *testing.T) {
+	r, err := proxy.NewRouter(map[string]string{
+		"greet":                 "greet:1",
+		"greet.v1.GreetService": "greet-v1:1",
+		"calc":                  "calc:1",
+	}, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	defer r.Close()
+
+	for _, tt := range []struct {
+		method string
+		want   string
+	}{
+		{"/greet.v1.GreetService/Greet", "greet-v1:1"},
+		{"/greet.GreatService/Greet", "greet:1"},
+		{"/greet.v2.GreetService/Greet", "greet:1"},
+		{"/calc.Sum/Add", "calc:1"},
+		{"/calculator.v1.CalculatorService/Sum", ""},
+		{"/greeter.Greeter/Greet", ""},
+	} {
+		cc, ok := r.Backend(tt.method)
+		got := ""
+		if ok {
+			got = cc.Target()
+		}
+		if got != tt.want {
+			t.Errorf("Backend(%s) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+}
+
+// serve starts a server on a local TCP port and returns its address.
+func serve(t *testing.T, register func(*grpc.Server), opts ...grpc.ServerOption) string {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := grpc.NewServer(opts...)
+	register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+// start serves a proxy in front of GreetService and CalculatorService.
+func start(t *testing.T) *grpc.ClientConn {
+	tracker := legacy.NewTracker(legacy.Successors)
+	greet := serve(t, func(s *grpc.Server) { greetservice.Register(s, &greetservice.Server{Out: io.Discard}) },
+		grpc.ChainUnaryInterceptor(tracker.UnaryInterceptor, validate.UnaryInterceptor))
+	calc := serve(t, func(s *grpc.Server) { calcservice.Register(s, &calcservice.Server{Out: io.Discard}) })
+
+	r, err := proxy.NewRouter(map[string]string{"greet": greet, "calculator": calc},
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	h := harness.New(t, func(s *grpc.Server) {
+		healthpb.RegisterHealthServer(s, health.NewServer())
+	}, grpc.UnknownServiceHandler(r.Handler), grpc.ForceServerCodec(rawcodec.Codec{}))
+	return h.Conn
+}
+
+func greeting(name string) *greetv1.Greeting {
+	return &greetv1.Greeting{FirstName: name}
+}
+
+func TestForward(t *testing.T) {
+	cc := start(t)
+	greet := greetv1.NewGreetServiceClient(cc)
+	ctx := context.Background()
+
+	res, err := greet.Greet(ctx, &greetv1.GreetRequest{Greeting: greeting("Ann")})
+	if err != nil || res.GetResult() != "Hello Ann" {
+		t.Errorf("Greet = %v, %v; want Hello Ann", res, err)
+	}
+
+	many, err := greet.GreetManyTimes(ctx, &greetv1.GreetManyTimesRequest{Greeting: greeting("Bo")})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	n := 0
+	for {
+		if _, err := many.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		n++
+	}
+	if n != 10 {
+		t.Errorf("GreetManyTimes sent %d greetings, want 10", n)
+	}
+
+	avg, err := calculatorv1.NewCalculatorServiceClient(cc).Average(ctx)
+	if err != nil {
+		t.Fatalf("Average: %v", err)
+	}
+	for _, num := range []int32{1, 2, 6} {
+		if err := avg.Send(&calculatorv1.AverageRequest{Number: num}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	if res, err := avg.CloseAndRecv(); err != nil || res.GetAverage() != 3 {
+		t.Errorf("Average = %v, %v; want 3", res, err)
+	}
+
+	every, err := greet.GreetEveryone(ctx)
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	var got []string
+	for _, name := range []string{"Cy", "Di"} {
+		if err := every.Send(&greetv1.GreetEveryoneRequest{Greeting: greeting(name)}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		res, err := every.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		got = append(got, res.GetResult())
+	}
+	every.CloseSend()
+	if _, err := every.Recv(); err != io.EOF {
+		t.Errorf("Recv after CloseSend = %v, want io.EOF", err)
+	}
+	if want := []string{"Hello Cy !", "Hello Di !"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GreetEveryone = %q, want %q", got, want)
+	}
+}
+
+func TestForwardStatusAndHeader(t *testing.T) {
+	cc := start(t)
+
+	_, err := greetv1.NewGreetServiceClient(cc).Greet(context.Background(), &greetv1.GreetRequest{})
+	if st := status.Convert(err); st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+		t.Errorf("Greet without greeting = %v, want InvalidArgument with details", err)
+	}
+
+	var header metadata.MD
+	_, err = greetpb.NewGreatServiceClient(cc).Greet(context.Background(),
+		&greetpb.GreatRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("legacy Greet: %v", err)
+	}
+	if got := header.Get(legacy.SuccessorHeader); len(got) != 1 || got[0] != "greet.v1.GreetService" {
+		t.Errorf("%s = %v, want greet.v1.GreetService", legacy.SuccessorHeader, got)
+	}
+}
+
+func TestForwardClientAddress(t *testing.T) {
+	a, err := auth.New(config.Auth{})
+	if err != nil {
+		t.Fatalf("auth.New: %v", err)
+	}
+	var seen string
+	backend := serve(t, func(s *grpc.Server) { greetservice.Register(s, &greetservice.Server{Out: io.Discard}) },
+		grpc.ChainUnaryInterceptor(a.UnaryInterceptor, func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			seen = auth.ClientAddr(ctx)
+			return handler(ctx, req)
+		}))
+	r, err := proxy.NewRouter(map[string]string{"greet": backend}, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	h := harness.New(t, func(*grpc.Server) {},
+		grpc.UnknownServiceHandler(r.Handler),
+		grpc.ForceServerCodec(rawcodec.Codec{}),
+		grpc.StreamInterceptor(a.StreamInterceptor),
+	)
+
+	// The proxy does not trust its bufconn peer, so the client cannot pose
+	// as another address.
+	ctx := metadata.AppendToOutgoingContext(context.Background(), auth.ForwardedForHeader, "198.51.100.7")
+	if _, err := greetv1.NewGreetServiceClient(h.Conn).Greet(ctx, &greetv1.GreetRequest{Greeting: greeting("Ann")}); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	if seen != "bufconn" {
+		t.Errorf("backend saw client %q, want the proxy's peer bufconn", seen)
+	}
+}
+
+func TestUnknownService(t *testing.T) {
+	cc := start(t)
+
+	err := cc.Invoke(context.Background(), "/weather.Forecast/Today", &greetv1.GreetRequest{}, &greetv1.GreetResponse{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("unrouted call = %v, want Unimplemented", err)
+	}
+	// Services registered on the proxy are served by it.
+	res, err := healthpb.NewHealthClient(cc).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil || res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check = %v, %v; want SERVING", res, err)
+	}
+}
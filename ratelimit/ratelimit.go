@@ -0,0 +1,195 @@
+// Package ratelimit provides server interceptors that limit each caller, as
+// named by auth.Identity, to a token bucket per method and to a number of
+// concurrently open streams. Rejected calls fail with ResourceExhausted; when
+// waiting helps, the status carries a RetryInfo detail and a retry-after
+// header in whole seconds.
+package ratelimit
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryAfterHeader is the response header holding the seconds to wait.
+const RetryAfterHeader = "retry-after"
+
+// idleBucket is how long an unused bucket is kept. By then it has refilled
+// for any sensible limit, so dropping it changes nothing for the caller.
+const idleBucket = 10 * time.Minute
+
+type key struct {
+	identity string
+	method   string
+}
+
+type bucket struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// Limiter holds the buckets and stream counts. Its settings can be replaced
+// with Update while the server runs.
+type Limiter struct {
+	mu        sync.Mutex
+	cfg       config.RateLimit
+	buckets   map[key]*bucket
+	streams   map[key]int
+	lastSweep time.Time
+}
+
+// New returns a Limiter enforcing cfg.
+func New(cfg config.RateLimit) *Limiter {
+	return &Limiter{
+		cfg:       cfg,
+		buckets:   map[key]*bucket{},
+		streams:   map[key]int{},
+		lastSweep: time.Now(),
+	}
+}
+
+// Update replaces the settings. Existing buckets keep their tokens but take
+// the new rate and burst.
+func (l *Limiter) Update(cfg config.RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cfg = cfg
+	for k, b := range l.buckets {
+		limit := l.limit(k.method)
+		if limit.Rate <= 0 {
+			delete(l.buckets, k)
+			continue
+		}
+		b.limiter.SetLimit(rate.Limit(limit.Rate))
+		b.limiter.SetBurst(burst(limit))
+	}
+}
+
+func (l *Limiter) limit(method string) config.Limit {
+	if limit, ok := l.cfg.Methods[method]; ok {
+		return limit
+	}
+	return l.cfg.Default
+}
+
+func (l *Limiter) maxStreams(method string) int {
+	if n, ok := l.cfg.MethodStreams[method]; ok {
+		return n
+	}
+	return l.cfg.MaxStreams
+}
+
+func burst(limit config.Limit) int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+	return int(math.Max(1, math.Ceil(limit.Rate)))
+}
+
+// take removes a token from the caller's bucket. When the bucket is empty it
+// returns false and how long until a token is available.
+func (l *Limiter) take(k key) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > idleBucket {
+		for bk, b := range l.buckets {
+			if now.Sub(b.seen) > idleBucket {
+				delete(l.buckets, bk)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	limit := l.limit(k.method)
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+	b, ok := l.buckets[k]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), burst(limit))}
+		l.buckets[k] = b
+	}
+	b.seen = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// openStream counts a stream of the caller. It returns false when the caller
+// is at its limit, otherwise release must be called when the stream ends.
+func (l *Limiter) openStream(k key) (release func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if max := l.maxStreams(k.method); max > 0 && l.streams[k] >= max {
+		return nil, false
+	}
+	l.streams[k]++
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.streams[k]--; l.streams[k] <= 0 {
+			delete(l.streams, k)
+		}
+	}, true
+}
+
+func exhausted(ctx context.Context, k key, delay time.Duration) error {
+	st := status.Newf(codes.ResourceExhausted, "rate limit exceeded for %s on %s", k.identity, k.method)
+	if delay <= 0 {
+		return st.Err()
+	}
+
+	seconds := int(math.Ceil(delay.Seconds()))
+	grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, strconv.Itoa(seconds)))
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// UnaryInterceptor rejects unary calls over the caller's rate.
+func (l *Limiter) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	k := key{identity: auth.Identity(ctx), method: info.FullMethod}
+	if ok, delay := l.take(k); !ok {
+		return nil, exhausted(ctx, k, delay)
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor rejects streams over the caller's rate or over its
+// concurrent stream limit. Opening a stream takes one token, the messages
+// on it are not counted.
+func (l *Limiter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := ss.Context()
+	k := key{identity: auth.Identity(ctx), method: info.FullMethod}
+	if ok, delay := l.take(k); !ok {
+		return exhausted(ctx, k, delay)
+	}
+
+	release, ok := l.openStream(k)
+	if !ok {
+		return status.Errorf(codes.ResourceExhausted, "too many concurrent streams for %s on %s", k.identity, k.method)
+	}
+	defer release()
+	return handler(srv, ss)
+}
@@ -0,0 +1,145 @@
+package ratelimit_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+)
+
+const method = "/greet.v1.GreetService/Greet"
+
+// limiter returns a Limiter allowing one call per caller and hour behind an
+// Authenticator accepting the API key "k1".
+func limiter(t *testing.T) (*auth.Authenticator, *ratelimit.Limiter) {
+	a, err := auth.New(config.Auth{APIKeys: []string{"k1"}})
+	if err != nil {
+		t.Fatalf("auth.New: %v", err)
+	}
+	return a, ratelimit.New(config.RateLimit{Default: config.Limit{Rate: 1.0 / 3600}})
+}
+
+// incoming returns the context of a call from the peer at addr with the
+// metadata kv.
+func incoming(addr string, kv ...string) context.Context {
+	tcp, _ := net.ResolveTCPAddr("tcp", addr)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tcp})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(kv...))
+}
+
+// unary makes a call through a and l.
+func unary(a *auth.Authenticator, l *ratelimit.Limiter, ctx context.Context) error {
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	_, err := a.UnaryInterceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		return l.UnaryInterceptor(ctx, req, info, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+	})
+	return err
+}
+
+func TestLimit(t *testing.T) {
+	a, l := limiter(t)
+	ctx := incoming("203.0.113.5:4000")
+
+	if err := unary(a, l, ctx); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	err := unary(a, l, ctx)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second call = %v, want ResourceExhausted", err)
+	}
+	var retry *errdetails.RetryInfo
+	for _, d := range status.Convert(err).Details() {
+		if r, ok := d.(*errdetails.RetryInfo); ok {
+			retry = r
+		}
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() <= 0 {
+		t.Errorf("details = %v, want a RetryInfo with a delay", status.Convert(err).Details())
+	}
+}
+
+func TestUnverifiedKeysShareTheAddressBucket(t *testing.T) {
+	a, l := limiter(t)
+	if err := unary(a, l, incoming("203.0.113.5:4000")); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+
+	// Unknown keys are refused outright, so they cannot buy a new bucket.
+	err := unary(a, l, incoming("203.0.113.5:4001", auth.APIKeyHeader, "random"))
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("call with an unknown key = %v, want Unauthenticated", err)
+	}
+	// A client dialing directly cannot claim another address.
+	err = unary(a, l, incoming("203.0.113.5:4002", auth.ForwardedForHeader, "198.51.100.7"))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("call forwarding for another address = %v, want ResourceExhausted", err)
+	}
+}
+
+func TestCallersHaveTheirOwnBuckets(t *testing.T) {
+	a, l := limiter(t)
+	for i, ctx := range []context.Context{
+		incoming("203.0.113.5:4000"),
+		incoming("203.0.113.5:4000", auth.APIKeyHeader, "k1"),
+		// Browsers behind the gateway of the server itself.
+		incoming("127.0.0.1:5000", auth.ForwardedForHeader, "198.51.100.7"),
+		incoming("127.0.0.1:5000", auth.ForwardedForHeader, "198.51.100.8"),
+	} {
+		if err := unary(a, l, ctx); err != nil {
+			t.Errorf("first call of caller %d: %v", i, err)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	a, l := limiter(t)
+	ctx := incoming("203.0.113.5:4000")
+	if err := unary(a, l, ctx); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+
+	l.Update(config.RateLimit{})
+	if err := unary(a, l, ctx); err != nil {
+		t.Errorf("call after removing the limit: %v", err)
+	}
+}
+
+type stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *stream) Context() context.Context { return s.ctx }
+
+func TestMaxStreams(t *testing.T) {
+	l := ratelimit.New(config.RateLimit{MaxStreams: 1})
+	info := &grpc.StreamServerInfo{FullMethod: "/greet.v1.GreetService/GreetEveryone"}
+	ss := &stream{ctx: incoming("203.0.113.5:4000")}
+
+	err := l.StreamInterceptor(nil, ss, info, func(any, grpc.ServerStream) error {
+		if err := l.StreamInterceptor(nil, ss, info, func(any, grpc.ServerStream) error { return nil }); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("second concurrent stream = %v, want ResourceExhausted", err)
+		}
+		other := &stream{ctx: incoming("198.51.100.7:4000")}
+		if err := l.StreamInterceptor(nil, other, info, func(any, grpc.ServerStream) error { return nil }); err != nil {
+			t.Errorf("stream of another caller: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("first stream: %v", err)
+	}
+	if err := l.StreamInterceptor(nil, ss, info, func(any, grpc.ServerStream) error { return nil }); err != nil {
+		t.Errorf("stream after the first ended: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+// Package rawcodec is a gRPC codec that moves message bytes without decoding
+// them, for code that forwards calls it does not have Go types for.
+package rawcodec
+
+import (
+	"fmt"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec marshals and unmarshals *[]byte values as they are and protobuf
+// messages as usual, so a server forced to use it for proxied calls still
+// serves the services registered on it. It reports itself as "proto" so the
+// content-type on the wire stays application/grpc+proto.
+type Codec struct{}
+
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case *[]byte:
+		return *v, nil
+	case proto.Message:
+		return proto.Marshal(v)
+	}
+	return nil, fmt.Errorf("rawcodec: cannot marshal %T", v)
+}
+
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	switch v := v.(type) {
+	case *[]byte:
+		*v = append((*v)[:0], data...)
+		return nil
+	case proto.Message:
+		return proto.Unmarshal(data, v)
+	}
+	return fmt.Errorf("rawcodec: cannot unmarshal into %T", v)
+}
+
+func (Codec) Name() string {
+	return "proto"
+}
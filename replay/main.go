@@ -0,0 +1,333 @@
+// Command replay sends the calls recorded in audit logs, see package audit,
+// to a server again and reports those whose responses or status differ from
+// the recorded ones.
+//
+//	replay audit.log
+//	replay -addr staging:50052 -method /calculator.v1.CalculatorService/Sum audit.log.2 audit.log.1 audit.log
+//
+// Every request of a call is sent in the recorded order before its responses
+// are read, so bidirectional streams are compared as a whole. Calls are sent
+// with their recorded metadata, except idempotency keys and cache-control,
+// so they run again instead of returning stored responses; credentials are
+// not recorded, pass them with -token. Records whose messages were truncated
+// are sent but not compared. replay exits with 1 when a call differs.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ferza17/grpc-course/audit"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/cache"
+	// Register the services the records refer to.
+	_ "github.com/ferza17/grpc-course/calculator/calculatorpb"
+	_ "github.com/ferza17/grpc-course/calculator/v1"
+	_ "github.com/ferza17/grpc-course/greet/greetpb"
+	_ "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/idempotency"
+	"github.com/ferza17/grpc-course/rpcclient"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultAddrs are the local servers by proto package prefix.
+var defaultAddrs = map[string]string{
+	"greet":      "localhost:50051",
+	"calculator": "localhost:50052",
+}
+
+var (
+	addr        = flag.String("addr", "", "server address, defaults to the local port of each call's service")
+	method      = flag.String("method", "", "replay only this full method name")
+	token       = flag.String("token", "", "bearer token sent as authorization metadata")
+	callTimeout = flag.Duration("timeout", 10*time.Second, "deadline of each call")
+	verbose     = flag.Bool("v", false, "also print the calls that match")
+)
+
+// usageError is returned for bad command lines, it exits with 2.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: replay [flags] audit-log...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	differ, err := run(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		var uerr usageError
+		if errors.As(err, &uerr) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	if differ {
+		os.Exit(1)
+	}
+}
+
+// summary counts the replayed calls by outcome.
+type summary struct {
+	same, different, unchecked int
+}
+
+func run(paths []string) (bool, error) {
+	if len(paths) == 0 {
+		return false, usageError{"expected at least one audit log"}
+	}
+
+	r := &replayer{conns: map[string]*grpc.ClientConn{}}
+	defer r.close()
+	var sum summary
+	for _, path := range paths {
+		if err := r.file(path, &sum); err != nil {
+			return false, err
+		}
+	}
+	fmt.Printf("%d calls replayed: %d same, %d different, %d not compared\n",
+		sum.same+sum.different+sum.unchecked, sum.same, sum.different, sum.unchecked)
+	return sum.different > 0, nil
+}
+
+type replayer struct {
+	conns map[string]*grpc.ClientConn
+}
+
+func (r *replayer) close() {
+	for _, cc := range r.conns {
+		cc.Close()
+	}
+}
+
+// conn returns the connection to the server of fullMethod.
+func (r *replayer) conn(fullMethod string) (*grpc.ClientConn, error) {
+	target := *addr
+	if target == "" {
+		pkg, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), ".")
+		target = defaultAddrs[pkg]
+	}
+	if target == "" {
+		return nil, fmt.Errorf("no server for %s, use -addr", fullMethod)
+	}
+	if cc, ok := r.conns[target]; ok {
+		return cc, nil
+	}
+	cc, err := rpcclient.Dial(context.Background(), target, rpcclient.Options{Credentials: insecure.NewCredentials()})
+	if err != nil {
+		return nil, err
+	}
+	r.conns[target] = cc
+	return cc, nil
+}
+
+// file replays the records of path. They are read before any is sent, so a
+// server appending to path while replaying does not replay its own records.
+func (r *replayer) file(path string, sum *summary) error {
+	records, err := readRecords(path)
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range records {
+		if *method != "" && rec.Method != *method {
+			continue
+		}
+
+		diffs, err := r.replay(rec.Record)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", path, rec.line, err)
+		}
+		where := fmt.Sprintf("%s:%d %s", path, rec.line, rec.Method)
+		switch {
+		case rec.Truncated:
+			sum.unchecked++
+			if *verbose {
+				fmt.Printf("SENT %s: truncated record, not compared\n", where)
+			}
+		case len(diffs) > 0:
+			sum.different++
+			fmt.Printf("DIFF %s\n", where)
+			for _, d := range diffs {
+				fmt.Printf("  %s\n", d)
+			}
+		default:
+			sum.same++
+			if *verbose {
+				fmt.Printf("SAME %s\n", where)
+			}
+		}
+	}
+	return nil
+}
+
+// record is a Record and the line it was read from.
+type record struct {
+	audit.Record
+	line int
+}
+
+func readRecords(path string) ([]record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []record
+	lines := bufio.NewScanner(f)
+	lines.Buffer(nil, 64<<20)
+	for n := 1; lines.Scan(); n++ {
+		rec := record{line: n}
+		if err := json.Unmarshal(lines.Bytes(), &rec.Record); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+		}
+		records = append(records, rec)
+	}
+	return records, lines.Err()
+}
+
+// notReplayed are the recorded headers that would keep a replayed call from
+// running as a new one: a stored response returned for its idempotency key
+// or its cache entry, or the address of the original client.
+var notReplayed = []string{idempotency.Header, cache.ControlHeader, auth.ForwardedForHeader}
+
+// replay sends rec and returns how the outcome differs from the record.
+func (r *replayer) replay(rec audit.Record) ([]string, error) {
+	md, err := methodDescriptor(rec.Method)
+	if err != nil {
+		return nil, err
+	}
+	in, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return nil, err
+	}
+	out, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]proto.Message, len(rec.Requests))
+	for i, data := range rec.Requests {
+		requests[i] = in.New().Interface()
+		if err := protojson.Unmarshal(data, requests[i]); err != nil {
+			return nil, fmt.Errorf("request %d: %v", i, err)
+		}
+	}
+	cc, err := r.conn(rec.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	header := rec.Metadata.Copy()
+	if header == nil {
+		header = metadata.MD{}
+	}
+	for _, key := range notReplayed {
+		delete(header, key)
+	}
+	if *token != "" {
+		header.Set("authorization", "Bearer "+*token)
+	}
+	ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(context.Background(), header), *callTimeout)
+	defer cancel()
+
+	responses, err := invoke(ctx, cc, md, out, requests)
+	var diffs []string
+	if got := status.Code(err).String(); got != rec.Code {
+		diffs = append(diffs, fmt.Sprintf("status: recorded %s, got %s: %s", rec.Code, got, status.Convert(err).Message()))
+	}
+	for i := 0; i < len(rec.Responses) || i < len(responses); i++ {
+		switch {
+		case i >= len(responses):
+			diffs = append(diffs, fmt.Sprintf("response %d: recorded %s, got none", i, rec.Responses[i]))
+		case i >= len(rec.Responses):
+			diffs = append(diffs, fmt.Sprintf("response %d: recorded none, got %s", i, compact(responses[i])))
+		default:
+			want := out.New().Interface()
+			if err := protojson.Unmarshal(rec.Responses[i], want); err != nil {
+				return nil, fmt.Errorf("response %d: %v", i, err)
+			}
+			if !proto.Equal(want, responses[i]) {
+				diffs = append(diffs, fmt.Sprintf("response %d: recorded %s, got %s", i, rec.Responses[i], compact(responses[i])))
+			}
+		}
+	}
+	return diffs, nil
+}
+
+// invoke sends every request, then reads responses until the end of the
+// stream. It returns the call's error with the responses received.
+func invoke(ctx context.Context, cc *grpc.ClientConn, md protoreflect.MethodDescriptor, out protoreflect.MessageType, requests []proto.Message) ([]proto.Message, error) {
+	desc := &grpc.StreamDesc{ServerStreams: md.IsStreamingServer(), ClientStreams: md.IsStreamingClient()}
+	stream, err := cc.NewStream(ctx, desc, fullMethod(md))
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range requests {
+		if err := stream.SendMsg(req); err != nil {
+			break // the status is returned by RecvMsg
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	var responses []proto.Message
+	for {
+		res := out.New().Interface()
+		err := stream.RecvMsg(res)
+		if err == io.EOF {
+			return responses, nil
+		}
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, res)
+	}
+}
+
+func methodDescriptor(fullMethod string) (protoreflect.MethodDescriptor, error) {
+	name := strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", ".")
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("unknown method %s", fullMethod)
+	}
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("unknown method %s", fullMethod)
+	}
+	return md, nil
+}
+
+func fullMethod(md protoreflect.MethodDescriptor) string {
+	return "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+}
+
+func compact(m proto.Message) string {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
@@ -0,0 +1,91 @@
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error is returned by every call made through a connection from Dial. It
+// keeps the gRPC status, so status.FromError still works on it, and matches
+// the sentinel errors below by code:
+//
+//	if errors.Is(err, rpcclient.ErrUnavailable) { ... }
+type Error struct {
+	// Method is the full method name, or "dial <addr>" for connection errors.
+	Method   string
+	Code     codes.Code
+	Message  string
+	Attempts int
+
+	status *status.Status
+}
+
+// Sentinel errors for the status codes callers usually branch on.
+var (
+	ErrCanceled          = &Error{Code: codes.Canceled}
+	ErrInvalidArgument   = &Error{Code: codes.InvalidArgument}
+	ErrDeadlineExceeded  = &Error{Code: codes.DeadlineExceeded}
+	ErrNotFound          = &Error{Code: codes.NotFound}
+	ErrAlreadyExists     = &Error{Code: codes.AlreadyExists}
+	ErrPermissionDenied  = &Error{Code: codes.PermissionDenied}
+	ErrResourceExhausted = &Error{Code: codes.ResourceExhausted}
+	ErrUnimplemented     = &Error{Code: codes.Unimplemented}
+	ErrInternal          = &Error{Code: codes.Internal}
+	ErrUnavailable       = &Error{Code: codes.Unavailable}
+	ErrUnauthenticated   = &Error{Code: codes.Unauthenticated}
+)
+
+func (e *Error) Error() string {
+	if e.Method == "" {
+		return fmt.Sprintf("rpc error: code = %s", e.Code)
+	}
+	msg := fmt.Sprintf("%s: code = %s desc = %s", e.Method, e.Code, e.Message)
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (after %d attempts)", e.Attempts)
+	}
+	return msg
+}
+
+// Is reports whether target is the sentinel for e's code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Method == "" && t.Code == e.Code
+}
+
+// GRPCStatus lets status.FromError and status.Code see through Error.
+func (e *Error) GRPCStatus() *status.Status {
+	if e.status == nil {
+		return status.New(e.Code, e.Message)
+	}
+	return e.status
+}
+
+// Temporary reports whether the same call may succeed when tried again later.
+func (e *Error) Temporary() bool {
+	switch e.Code {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+func wrap(method string, err error, attempts int) error {
+	if err == nil {
+		return nil
+	}
+	var rerr *Error
+	if errors.As(err, &rerr) {
+		return err
+	}
+
+	var st *status.Status
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		st = status.FromContextError(err)
+	} else {
+		st = status.Convert(err)
+	}
+	return &Error{Method: method, Code: st.Code(), Message: st.Message(), Attempts: attempts, status: st}
+}
@@ -0,0 +1,147 @@
+package rpcclient
+
+import (
+	"context"
+	crand "crypto/rand"
+	"github.com/ferza17/grpc-course/idempotency"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"math/rand"
+	"time"
+)
+
+// attemptsKey carries the attempt count from the retry interceptor out to the
+// error interceptor.
+type attemptsKey struct{}
+
+func errorInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	attempts := 1
+	err := invoker(context.WithValue(ctx, attemptsKey{}, &attempts), method, req, reply, cc, opts...)
+	return wrap(method, err, attempts)
+}
+
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func retryInterceptor(policy RetryPolicy, idempotent []string) grpc.UnaryClientInterceptor {
+	retryable := map[string]bool{}
+	for _, method := range idempotent {
+		retryable[method] = true
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !retryable[method] || policy.MaxAttempts <= 1 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		// Every attempt carries the same key, so a server that completed an
+		// attempt whose response was lost answers the retry from its store.
+		if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get(idempotency.Header)) == 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, idempotency.Header, crand.Text())
+		}
+
+		backoff := policy.InitialBackoff
+		for attempt := 1; ; attempt++ {
+			if counter, ok := ctx.Value(attemptsKey{}).(*int); ok {
+				*counter = attempt
+			}
+
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= policy.MaxAttempts || !policy.retryable(status.Code(err)) {
+				return err
+			}
+
+			// Jitter keeps replicas of one caller from retrying in lockstep.
+			wait := time.Duration(float64(backoff) * (0.8 + 0.4*rand.Float64()))
+			if status.Code(err) == codes.ResourceExhausted {
+				// Only a server asking to come back later, as the rate
+				// limiter does, is worth retrying. An oversized message
+				// fails the same way every time.
+				delay, ok := retryDelay(err)
+				if !ok {
+					return err
+				}
+				if delay > wait {
+					wait = delay
+				}
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+
+			backoff = time.Duration(float64(backoff) * policy.Multiplier)
+			if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+				backoff = policy.MaxBackoff
+			}
+		}
+	}
+}
+
+// retryDelay returns the delay of the RetryInfo detail of err.
+func retryDelay(err error) (time.Duration, bool) {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+func (p RetryPolicy) retryable(code codes.Code) bool {
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func streamErrorInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, wrap(method, err, 1)
+	}
+	return &errorStream{ClientStream: stream, method: method}, nil
+}
+
+// errorStream turns stream errors into *Error, io.EOF is passed through as
+// it marks the end of the stream rather than a failure.
+type errorStream struct {
+	grpc.ClientStream
+	method string
+}
+
+func (s *errorStream) wrap(err error) error {
+	if err == io.EOF {
+		return err
+	}
+	return wrap(s.method, err, 1)
+}
+
+func (s *errorStream) SendMsg(m interface{}) error {
+	return s.wrap(s.ClientStream.SendMsg(m))
+}
+
+func (s *errorStream) RecvMsg(m interface{}) error {
+	return s.wrap(s.ClientStream.RecvMsg(m))
+}
+
+func (s *errorStream) CloseSend() error {
+	return s.wrap(s.ClientStream.CloseSend())
+}
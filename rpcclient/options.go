@@ -0,0 +1,190 @@
+// Package rpcclient holds the connection management shared by greetclient and
+// calcclient: dialing with a deadline, default per-call deadlines, retries with
+// exponential backoff for idempotent methods, keepalive, message size limits,
+// compression and typed errors.
+package rpcclient
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/discovery"
+	// Also registers the gzip and zstd compressors.
+	"github.com/ferza17/grpc-course/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// RetryPolicy controls how failed calls to idempotent methods are retried.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt, 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt, each later wait is
+	// Multiplier times longer, up to MaxBackoff. Waits are jittered by ±20%.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// RetryableCodes are the status codes worth another attempt.
+	RetryableCodes []codes.Code
+}
+
+// Options configure a client connection.
+type Options struct {
+	// DialTimeout bounds how long Dial waits for the connection to be ready.
+	DialTimeout time.Duration
+	// Timeout is the deadline given to unary calls whose context has none,
+	// retries included.
+	Timeout time.Duration
+	// StreamTimeout is the deadline given to streams driven to completion by
+	// the client packages, 0 leaves them unbounded.
+	StreamTimeout time.Duration
+	// Retry applies to the methods passed to Dial as idempotent.
+	Retry RetryPolicy
+	// Keepalive pings the server on idle connections. Time must not be lower
+	// than the server's enforcement minimum or the server closes the
+	// connection. Servers of this repository accept 30 seconds, see
+	// transport.DefaultKeepalive; lower Time for them to keep long quiet
+	// streams alive through NATs.
+	Keepalive keepalive.ClientParameters
+	// Balancer spreads calls over the backends the target resolves to, see
+	// the discovery package for targets and names. Empty picks the first
+	// backend that connects.
+	Balancer string
+	// HealthCheck ejects backends whose grpc.health.v1 service is not SERVING.
+	// Without Balancer it balances round robin, as the first backend that
+	// connects would be kept regardless of its health.
+	HealthCheck bool
+	// MaxRecvMsgSize and MaxSendMsgSize bound message sizes in bytes, zero
+	// keeps the grpc-go defaults of 4MB received and unlimited sent.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	// Compressor compresses every request, "gzip" or "zstd". A single call
+	// can choose another with grpc.UseCompressor.
+	Compressor string
+	// Credentials default to plaintext.
+	Credentials credentials.TransportCredentials
+	// DialOptions are appended to the options built from the fields above.
+	DialOptions []grpc.DialOption
+}
+
+// DefaultOptions returns the settings used when a field of Options is zero.
+func DefaultOptions() Options {
+	return Options{
+		DialTimeout: 10 * time.Second,
+		Timeout:     5 * time.Second,
+		Retry: RetryPolicy{
+			MaxAttempts:    4,
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     2 * time.Second,
+			Multiplier:     2,
+			RetryableCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted},
+		},
+		// Servers on the grpc-go defaults, vcr among them, close connections
+		// pinging more often than every 5 minutes.
+		Keepalive: keepalive.ClientParameters{
+			Time:    5 * time.Minute,
+			Timeout: 20 * time.Second,
+		},
+	}
+}
+
+func (o Options) withDefaults() Options {
+	def := DefaultOptions()
+	if o.DialTimeout == 0 {
+		o.DialTimeout = def.DialTimeout
+	}
+	if o.Timeout == 0 {
+		o.Timeout = def.Timeout
+	}
+	if o.Retry.MaxAttempts == 0 {
+		o.Retry = def.Retry
+	}
+	if o.Keepalive.Time == 0 {
+		o.Keepalive = def.Keepalive
+	}
+	if o.Credentials == nil {
+		o.Credentials = insecure.NewCredentials()
+	}
+	return o
+}
+
+// Dial connects to addr and waits until the connection is ready, the
+// context is done or DialTimeout passes. Calls to the full method names in
+// idempotent ("/greet.GreatService/Greet") are retried according to
+// opts.Retry, every unary call gets opts.Timeout unless it has a deadline.
+// Retried calls send one idempotency key, see package idempotency, for all
+// their attempts unless the caller set one.
+//
+// Besides host:port and the targets of package discovery, addr may be a Unix
+// domain socket, "unix:///run/greet.sock", or a listener of this process
+// opened with transport.ListenInProcess, "inprocess:greet".
+func Dial(ctx context.Context, addr string, opts Options, idempotent ...string) (*grpc.ClientConn, error) {
+	opts = opts.withDefaults()
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(opts.Credentials),
+		grpc.WithKeepaliveParams(opts.Keepalive),
+		grpc.WithChainUnaryInterceptor(
+			errorInterceptor,
+			timeoutInterceptor(opts.Timeout),
+			retryInterceptor(opts.Retry, idempotent),
+		),
+		grpc.WithChainStreamInterceptor(streamErrorInterceptor),
+	}
+	var callOpts []grpc.CallOption
+	if opts.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(opts.MaxRecvMsgSize))
+	}
+	if opts.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(opts.MaxSendMsgSize))
+	}
+	if opts.Compressor != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(opts.Compressor))
+	}
+	if len(callOpts) > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+	if opts.Balancer != "" || opts.HealthCheck {
+		serviceConfig, err := discovery.ServiceConfig(opts.Balancer, opts.HealthCheck)
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithDefaultServiceConfig(serviceConfig))
+	}
+	target := addr
+	if transport.IsInProcess(addr) {
+		var inProcessOpts []grpc.DialOption
+		target, inProcessOpts = transport.InProcessDialOptions(addr)
+		dialOpts = append(dialOpts, inProcessOpts...)
+	}
+	cc, err := grpc.NewClient(target, append(dialOpts, opts.DialOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.DialTimeout)
+	defer cancel()
+	if err := waitReady(ctx, cc); err != nil {
+		cc.Close()
+		return nil, wrap("dial "+addr, err, 1)
+	}
+	return cc, nil
+}
+
+func waitReady(ctx context.Context, cc *grpc.ClientConn) error {
+	cc.Connect()
+	for {
+		state := cc.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !cc.WaitForStateChange(ctx, state) {
+			return status.Errorf(codes.Unavailable, "connection not ready, last state %s: %v", state, ctx.Err())
+		}
+	}
+}
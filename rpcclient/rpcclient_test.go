@@ -0,0 +1,225 @@
+package rpcclient_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ferza17/grpc-course/fake"
+	"github.com/ferza17/grpc-course/greet/greetfake"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	"github.com/ferza17/grpc-course/idempotency"
+	"github.com/ferza17/grpc-course/rpcclient"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"net"
+	"testing"
+	"time"
+)
+
+const greet = "/greet.GreatService/Greet"
+
+var ok = fake.Send(&greetpb.GreetResponse{Result: "hi"})
+
+// dial serves a greetfake and dials it through rpcclient.Dial with opts,
+// retrying Greet when idempotent is set.
+func dial(t *testing.T, opts rpcclient.Options, idempotent bool) (*greetfake.Server, greetpb.GreatServiceClient) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := greetfake.New()
+	s := grpc.NewServer()
+	greetpb.RegisterGreatServiceServer(s, f.Service())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	var methods []string
+	if idempotent {
+		methods = append(methods, greet)
+	}
+	cc, err := rpcclient.Dial(context.Background(), lis.Addr().String(), opts, methods...)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return f, greetpb.NewGreatServiceClient(cc)
+}
+
+// retryAfter returns a ResourceExhausted error asking to come back after d.
+func retryAfter(d time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, "slow down").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	if err != nil {
+		panic(err)
+	}
+	return st.Err()
+}
+
+func TestRetry(t *testing.T) {
+	opts := rpcclient.Options{Retry: rpcclient.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Multiplier:     2,
+		RetryableCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted},
+	}}
+	unavailable := fake.Fail(status.Error(codes.Unavailable, "down"))
+
+	for _, tt := range []struct {
+		name       string
+		idempotent bool
+		scripts    []fake.Script
+		wantCalls  int
+		wantErr    error
+	}{
+		{"unavailable then ok", true, []fake.Script{{unavailable}, {ok}}, 2, nil},
+		{"not idempotent", false, []fake.Script{{unavailable}, {ok}}, 1, rpcclient.ErrUnavailable},
+		{"attempts exhausted", true, []fake.Script{{unavailable}}, 3, rpcclient.ErrUnavailable},
+		{"code not retryable", true, []fake.Script{{fake.Fail(status.Error(codes.InvalidArgument, "bad"))}, {ok}}, 1, rpcclient.ErrInvalidArgument},
+		{"exhausted with retry info", true, []fake.Script{{fake.Fail(retryAfter(time.Millisecond))}, {ok}}, 2, nil},
+		{"exhausted without retry info", true, []fake.Script{{fake.Fail(status.Error(codes.ResourceExhausted, "too big"))}, {ok}}, 1, rpcclient.ErrResourceExhausted},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, c := dial(t, opts, tt.idempotent)
+			for _, script := range tt.scripts {
+				f.Greet.Enqueue(script...)
+			}
+
+			_, err := c.Greet(context.Background(), &greetpb.GreatRequest{})
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Greet error = %v, want %v", err, tt.wantErr)
+			}
+			if n := len(f.Greet.Calls()); n != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", n, tt.wantCalls)
+			}
+			var rerr *rpcclient.Error
+			if errors.As(err, &rerr) && (rerr.Method != greet || rerr.Attempts != tt.wantCalls) {
+				t.Errorf("error method %q after %d attempts, want %q after %d", rerr.Method, rerr.Attempts, greet, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryInfoDelay(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	f, c := dial(t, rpcclient.Options{}, true)
+	f.Greet.Enqueue(fake.Fail(retryAfter(delay))).Enqueue(ok)
+
+	start := time.Now()
+	if _, err := c.Greet(context.Background(), &greetpb.GreatRequest{}); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("retried after %v, want at least the server's %v", elapsed, delay)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		header []string
+	}{
+		{"generated", nil},
+		{"set by the caller", []string{idempotency.Header, "order-1"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, c := dial(t, rpcclient.Options{Retry: rpcclient.RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+				RetryableCodes: []codes.Code{codes.Unavailable},
+			}}, true)
+			f.Greet.Enqueue(fake.Fail(status.Error(codes.Unavailable, "down"))).
+				Enqueue(fake.Fail(status.Error(codes.Unavailable, "down"))).
+				Enqueue(ok)
+
+			ctx := context.Background()
+			if tt.header != nil {
+				ctx = metadata.AppendToOutgoingContext(ctx, tt.header...)
+			}
+			if _, err := c.Greet(ctx, &greetpb.GreatRequest{}); err != nil {
+				t.Fatalf("Greet: %v", err)
+			}
+
+			calls := f.Greet.Calls()
+			if len(calls) != 3 {
+				t.Fatalf("calls = %d, want 3", len(calls))
+			}
+			first := calls[0].Metadata.Get(idempotency.Header)
+			if len(first) != 1 || first[0] == "" || tt.header != nil && first[0] != tt.header[1] {
+				t.Fatalf("first attempt key = %q", first)
+			}
+			for i, call := range calls[1:] {
+				if got := call.Metadata.Get(idempotency.Header); len(got) != 1 || got[0] != first[0] {
+					t.Errorf("attempt %d key = %q, want %q", i+2, got, first[0])
+				}
+			}
+		})
+	}
+}
+
+func TestNoIdempotencyKeyWithoutRetries(t *testing.T) {
+	f, c := dial(t, rpcclient.Options{}, false)
+	f.Greet.Enqueue(ok)
+	if _, err := c.Greet(context.Background(), &greetpb.GreatRequest{}); err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	if got := f.Greet.Calls()[0].Metadata.Get(idempotency.Header); len(got) != 0 {
+		t.Errorf("key = %q on a call that is never retried", got)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	slow := fake.SendAfter(time.Second, &greetpb.GreetResponse{})
+
+	for _, tt := range []struct {
+		name     string
+		deadline time.Duration
+		want     error
+	}{
+		{"applied", 0, rpcclient.ErrDeadlineExceeded},
+		{"caller's deadline kept", 2 * time.Second, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, c := dial(t, rpcclient.Options{Timeout: timeout}, false)
+			f.Greet.Enqueue(slow)
+
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+			start := time.Now()
+			_, err := c.Greet(ctx, &greetpb.GreatRequest{})
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("Greet error = %v, want %v", err, tt.want)
+			}
+			if elapsed := time.Since(start); tt.want != nil && elapsed > 10*timeout {
+				t.Errorf("Greet returned after %v, want about %v", elapsed, timeout)
+			}
+		})
+	}
+}
+
+func TestTimeoutCoversRetries(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	f, c := dial(t, rpcclient.Options{Timeout: timeout, Retry: rpcclient.RetryPolicy{
+		MaxAttempts:    100,
+		InitialBackoff: 10 * time.Millisecond,
+		Multiplier:     1,
+		RetryableCodes: []codes.Code{codes.Unavailable},
+	}}, true)
+	f.Greet.Enqueue(fake.Fail(status.Error(codes.Unavailable, "down")))
+
+	start := time.Now()
+	_, err := c.Greet(context.Background(), &greetpb.GreatRequest{})
+	if !errors.Is(err, rpcclient.ErrUnavailable) && !errors.Is(err, rpcclient.ErrDeadlineExceeded) {
+		t.Fatalf("Greet error = %v, want Unavailable or DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*timeout {
+		t.Errorf("retries went on for %v, want about %v", elapsed, timeout)
+	}
+}
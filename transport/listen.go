@@ -0,0 +1,248 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ferza17/grpc-course/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Addresses other than host:port name the transport with a prefix, the same
+// ones gRPC clients accept as targets:
+//
+//	unix:///run/greet.sock  Unix domain socket at an absolute path
+//	unix:greet.sock         Unix domain socket at a relative path
+//	inprocess:greet         in-memory listener reachable from this process only
+const (
+	unixPrefix      = "unix:"
+	inProcessPrefix = "inprocess:"
+)
+
+var (
+	inProcessMu        sync.Mutex
+	inProcessListeners = map[string]*inProcessListener{}
+)
+
+// Listen listens on addr, a TCP host:port or one of the addresses above.
+// Unix domain sockets get cfg.SocketMode, a socket file left behind by a
+// previous process is removed first.
+func Listen(addr string, cfg config.Transport) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(addr, unixPrefix):
+		return listenUnix(unixPath(addr), cfg.SocketMode)
+	case strings.HasPrefix(addr, inProcessPrefix):
+		return ListenInProcess(strings.TrimPrefix(addr, inProcessPrefix))
+	}
+	return net.Listen("tcp", addr)
+}
+
+func unixPath(addr string) string {
+	path := strings.TrimPrefix(addr, unixPrefix)
+	if strings.HasPrefix(path, "//") {
+		return strings.TrimPrefix(path, "//")
+	}
+	return path
+}
+
+func listenUnix(path, mode string) (net.Listener, error) {
+	var perm fs.FileMode
+	if mode != "" {
+		m, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || m&^0o777 != 0 {
+			return nil, fmt.Errorf("socket mode %q is not octal permissions such as 0660", mode)
+		}
+		perm = fs.FileMode(m)
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("listen unix %s: file exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	if mode == "" {
+		return net.Listen("unix", path)
+	}
+
+	// The socket is created in a directory only this process can enter and
+	// moved into place once it has its mode, so no one connects while it
+	// still has the permissions of the umask.
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".socket-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "s")
+	lis, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmp, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	lis.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, perm); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	return &unixListener{UnixListener: lis, addr: &net.UnixAddr{Name: path, Net: "unix"}}, nil
+}
+
+// unixListener is a socket moved after it was bound: it reports and removes
+// the path it was moved to.
+type unixListener struct {
+	*net.UnixListener
+	addr *net.UnixAddr
+}
+
+func (l *unixListener) Addr() net.Addr {
+	return l.addr
+}
+
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	os.Remove(l.addr.Name)
+	return err
+}
+
+// ListenInProcess returns a listener whose connections are in-memory pipes
+// instead of sockets, for Go code embedding a server. Clients in the same
+// process reach it as "inprocess:"+name through DialInProcess, or
+// rpcclient.Dial. The name is free again once the listener is closed.
+func ListenInProcess(name string) (net.Listener, error) {
+	inProcessMu.Lock()
+	defer inProcessMu.Unlock()
+	if _, ok := inProcessListeners[name]; ok {
+		return nil, fmt.Errorf("listen inprocess:%s: address already in use", name)
+	}
+	lis := &inProcessListener{name: name, conns: make(chan net.Conn), done: make(chan struct{})}
+	inProcessListeners[name] = lis
+	return lis, nil
+}
+
+// inProcessListener hands one end of a net.Pipe to Accept for every dial and
+// unregisters its name when closed.
+type inProcessListener struct {
+	name  string
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func (l *inProcessListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *inProcessListener) dial(ctx context.Context) (net.Conn, error) {
+	client, server := net.Pipe()
+	var err error
+	select {
+	case l.conns <- &inProcessConn{Conn: server, addr: inProcessAddr(l.name)}:
+		return &inProcessConn{Conn: client, addr: inProcessAddr(l.name)}, nil
+	case <-l.done:
+		err = fmt.Errorf("dial inprocess:%s: %w", l.name, net.ErrClosed)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	client.Close()
+	server.Close()
+	return nil, err
+}
+
+func (l *inProcessListener) Close() error {
+	l.once.Do(func() {
+		inProcessMu.Lock()
+		delete(inProcessListeners, l.name)
+		inProcessMu.Unlock()
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *inProcessListener) Addr() net.Addr {
+	return inProcessAddr(l.name)
+}
+
+// inProcessConn reports the listener address for both ends, so servers see
+// in-process callers on the "inprocess" network.
+type inProcessConn struct {
+	net.Conn
+	addr inProcessAddr
+}
+
+func (c *inProcessConn) LocalAddr() net.Addr  { return c.addr }
+func (c *inProcessConn) RemoteAddr() net.Addr { return c.addr }
+
+type inProcessAddr string
+
+func (a inProcessAddr) Network() string { return "inprocess" }
+func (a inProcessAddr) String() string  { return inProcessPrefix + string(a) }
+
+// IsInProcess reports whether target is an "inprocess:" address.
+func IsInProcess(target string) bool {
+	return strings.HasPrefix(target, inProcessPrefix)
+}
+
+// DialInProcess connects to the in-process listener at addr, it has the
+// signature of grpc.WithContextDialer.
+func DialInProcess(ctx context.Context, addr string) (net.Conn, error) {
+	name := strings.TrimPrefix(addr, inProcessPrefix)
+	inProcessMu.Lock()
+	lis, ok := inProcessListeners[name]
+	inProcessMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("dial inprocess:%s: no such listener", name)
+	}
+	return lis.dial(ctx)
+}
+
+// InProcessDialOptions returns the options that route the in-process target
+// to its listener. gRPC has no resolver for the scheme, so the target
+// returned replaces it.
+func InProcessDialOptions(target string) (string, []grpc.DialOption) {
+	return "passthrough:///" + target, []grpc.DialOption{grpc.WithContextDialer(DialInProcess)}
+}
+
+// DialListener connects to lis from the process serving it, for handlers
+// such as the HTTP gateway that call the gRPC server through its listener.
+// TCP listeners are dialed on their IP, or loopback when they listen on every
+// interface.
+func DialListener(lis net.Listener, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	var target string
+	switch addr := lis.Addr().(type) {
+	case *net.TCPAddr:
+		// A listener on every interface is reached over loopback, any other
+		// on the address it is bound to.
+		host := "localhost"
+		if !addr.IP.IsUnspecified() && addr.IP != nil {
+			host = addr.IP.String()
+		}
+		target = net.JoinHostPort(host, strconv.Itoa(addr.Port))
+	case *net.UnixAddr:
+		target = unixPrefix + addr.Name
+	case inProcessAddr:
+		var inProcessOpts []grpc.DialOption
+		target, inProcessOpts = InProcessDialOptions(addr.String())
+		opts = append(inProcessOpts, opts...)
+	default:
+		return nil, errors.New("dial listener: unsupported address " + addr.String())
+	}
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	return grpc.NewClient(target, opts...)
+}
@@ -0,0 +1,178 @@
+package transport_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/greet/greetclient"
+	"github.com/ferza17/grpc-course/greet/greetservice"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/rpcclient"
+	"github.com/ferza17/grpc-course/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// serve serves GreetService on lis until the test ends.
+func serve(t *testing.T, lis net.Listener) {
+	s := grpc.NewServer()
+	greetservice.Register(s, &greetservice.Server{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+}
+
+// greet calls Greet through rpcclient at addr.
+func greet(t *testing.T, addr string) {
+	t.Helper()
+	c, err := greetclient.Dial(context.Background(), addr, rpcclient.Options{DialTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Dial(%s): %v", addr, err)
+	}
+	defer c.Close()
+	if got, err := c.Greet(context.Background(), "Ann", ""); err != nil || got != "Hello Ann" {
+		t.Errorf("Greet over %s = %q, %v; want Hello Ann", addr, got, err)
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "greet.sock")
+	// A socket left behind by a previous process is replaced.
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	lis, err := transport.Listen("unix://"+path, config.Transport{SocketMode: "0600"})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	serve(t, lis)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat socket: %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0o600 {
+		t.Errorf("socket mode = %v, want 0600", got)
+	}
+
+	greet(t, "unix://"+path)
+	greet(t, "unix:"+path)
+
+	cc, err := transport.DialListener(lis)
+	if err != nil {
+		t.Fatalf("DialListener: %v", err)
+	}
+	defer cc.Close()
+	res, err := greetv1.NewGreetServiceClient(cc).Greet(context.Background(), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Bo"}})
+	if err != nil || res.GetResult() != "Hello Bo" {
+		t.Errorf("Greet through DialListener = %v, %v; want Hello Bo", res, err)
+	}
+}
+
+func TestListenUnixLeavesOnlyTheSocket(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "greet.sock")
+	lis, err := transport.Listen("unix://"+path, config.Transport{SocketMode: "0660"})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	if got := lis.Addr().String(); got != path {
+		t.Errorf("Addr = %q, want %q", got, path)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "greet.sock" {
+		t.Errorf("directory holds %v, want the socket only", entries)
+	}
+	lis.Close()
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("socket left after Close: %v", err)
+	}
+}
+
+func TestDialListenerTCP(t *testing.T) {
+	// 127.0.0.2 is not reached through localhost.
+	lis, err := transport.Listen("127.0.0.2:0", config.Transport{})
+	if err != nil {
+		t.Skipf("listen on 127.0.0.2: %v", err)
+	}
+	serve(t, lis)
+	cc, err := transport.DialListener(lis)
+	if err != nil {
+		t.Fatalf("DialListener: %v", err)
+	}
+	defer cc.Close()
+	if _, err := greetv1.NewGreetServiceClient(cc).Greet(context.Background(), &greetv1.GreetRequest{}); err != nil {
+		t.Errorf("Greet through DialListener: %v", err)
+	}
+}
+
+func TestListenUnixErrors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(file, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transport.Listen("unix://"+file, config.Transport{}); err == nil {
+		t.Error("Listen over a regular file succeeded")
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("regular file removed: %v", err)
+	}
+	for _, mode := range []string{"rw-rw----", "660x", "01777"} {
+		if _, err := transport.Listen("unix://"+filepath.Join(dir, "s.sock"), config.Transport{SocketMode: mode}); err == nil {
+			t.Errorf("Listen with socket mode %q succeeded", mode)
+		}
+	}
+}
+
+func TestListenInProcess(t *testing.T) {
+	lis, err := transport.Listen("inprocess:greet-test", config.Transport{})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	if got := lis.Addr().String(); got != "inprocess:greet-test" {
+		t.Errorf("Addr = %q, want inprocess:greet-test", got)
+	}
+	if _, err := transport.ListenInProcess("greet-test"); err == nil {
+		t.Error("second listener on the same name succeeded")
+	}
+	var network string
+	s := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if p, ok := peer.FromContext(ctx); ok {
+			network = p.Addr.Network()
+		}
+		return handler(ctx, req)
+	}))
+	greetservice.Register(s, &greetservice.Server{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	greet(t, "inprocess:greet-test")
+	if network != "inprocess" {
+		t.Errorf("peer network = %q, want inprocess", network)
+	}
+	cc, err := transport.DialListener(lis)
+	if err != nil {
+		t.Fatalf("DialListener: %v", err)
+	}
+	defer cc.Close()
+	if _, err := greetv1.NewGreetServiceClient(cc).Greet(context.Background(), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Bo"}}); err != nil {
+		t.Errorf("Greet through DialListener: %v", err)
+	}
+
+	lis.Close()
+	if _, err := transport.DialInProcess(context.Background(), "inprocess:greet-test"); err == nil {
+		t.Error("DialInProcess succeeded after Close")
+	}
+	lis, err = transport.ListenInProcess("greet-test")
+	if err != nil {
+		t.Fatalf("ListenInProcess after Close: %v", err)
+	}
+	lis.Close()
+}
@@ -0,0 +1,49 @@
+// Package transport turns config.Transport into gRPC server options and
+// registers the compressors a call may negotiate: gzip and zstd. A server
+// answers with the compressor the client used, clients pick one per call
+// with grpc.UseCompressor or for every call with rpcclient.Options. Listen
+// opens the listeners of servers on TCP, Unix domain sockets or in memory.
+package transport
+
+import (
+	"github.com/ferza17/grpc-course/config"
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+	"time"
+)
+
+// DefaultKeepalive lets clients ping every 30 seconds, well below the 5
+// minute minimum grpc-go enforces by default, so long idle streams survive
+// NATs and load balancers that drop quiet connections.
+var DefaultKeepalive = config.Keepalive{
+	MinTime:             config.Duration(30 * time.Second),
+	PermitWithoutStream: true,
+}
+
+// ServerOptions returns the options for grpc.NewServer matching cfg.
+func ServerOptions(cfg config.Transport) []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if cfg.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(cfg.MaxSendMsgSize))
+	}
+
+	ka := cfg.Keepalive
+	opts = append(opts,
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     time.Duration(ka.MaxConnectionIdle),
+			MaxConnectionAge:      time.Duration(ka.MaxConnectionAge),
+			MaxConnectionAgeGrace: time.Duration(ka.MaxConnectionAgeGrace),
+			Time:                  time.Duration(ka.Time),
+			Timeout:               time.Duration(ka.Timeout),
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             time.Duration(ka.MinTime),
+			PermitWithoutStream: ka.PermitWithoutStream,
+		}),
+	)
+	return opts
+}
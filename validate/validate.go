@@ -0,0 +1,215 @@
+// Package validate checks messages against the rules their .proto files
+// declare with the (validate.v1.field) option, and provides server
+// interceptors that refuse invalid requests before they reach a handler.
+//
+// Rejected requests fail with InvalidArgument. The status carries a
+// BadRequest detail with one FieldViolation per broken rule, naming the field
+// by its path from the request, like "greeting.first_name".
+package validate
+
+import (
+	"context"
+	"fmt"
+	validatev1 "github.com/ferza17/grpc-course/validate/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// field is a field to check: its own rules, if any, and for messages the
+// rules of the fields inside.
+type field struct {
+	fd      protoreflect.FieldDescriptor
+	rules   *validatev1.FieldRules
+	pattern *regexp.Regexp
+}
+
+// fields caches the fields to check by message name.
+var fields sync.Map
+
+// fieldsOf returns the fields of md that have rules or hold messages. A
+// pattern that does not compile is a bug in the .proto file and panics.
+func fieldsOf(md protoreflect.MessageDescriptor) []field {
+	if fs, ok := fields.Load(md.FullName()); ok {
+		return fs.([]field)
+	}
+
+	var fs []field
+	all := md.Fields()
+	for i := 0; i < all.Len(); i++ {
+		f := field{fd: all.Get(i)}
+		if opts := f.fd.Options(); proto.HasExtension(opts, validatev1.E_Field) {
+			f.rules = proto.GetExtension(opts, validatev1.E_Field).(*validatev1.FieldRules)
+			if p := f.rules.GetPattern(); p != "" {
+				f.pattern = regexp.MustCompile(p)
+			}
+		}
+		if f.rules == nil && holds(f.fd) == nil {
+			continue
+		}
+		fs = append(fs, f)
+	}
+	fields.Store(md.FullName(), fs)
+	return fs
+}
+
+// holds returns the message type held by fd, or by the values of a map.
+func holds(fd protoreflect.FieldDescriptor) protoreflect.MessageDescriptor {
+	if fd.IsMap() {
+		return fd.MapValue().Message()
+	}
+	return fd.Message()
+}
+
+// Violations returns the rules m breaks, including those of the messages it
+// holds, in field order. It returns nil for a valid message.
+func Violations(m proto.Message) []*errdetails.BadRequest_FieldViolation {
+	var v violations
+	v.message("", m.ProtoReflect())
+	return v
+}
+
+// Check returns nil when m is valid and an InvalidArgument status error
+// listing the violations otherwise.
+func Check(m proto.Message) error {
+	v := Violations(m)
+	if len(v) == 0 {
+		return nil
+	}
+
+	desc := make([]string, len(v))
+	for i, fv := range v {
+		desc[i] = fv.Field + " " + fv.Description
+	}
+	st := status.Newf(codes.InvalidArgument, "invalid %s: %s", m.ProtoReflect().Descriptor().Name(), strings.Join(desc, "; "))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, format string, args ...any) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+// message checks the fields of m, naming them after prefix.
+func (v *violations) message(prefix string, m protoreflect.Message) {
+	for _, f := range fieldsOf(m.Descriptor()) {
+		name := prefix + string(f.fd.Name())
+		required := f.rules.GetRequired()
+
+		switch {
+		case f.fd.IsList():
+			list := m.Get(f.fd).List()
+			if required && list.Len() == 0 {
+				v.add(name, "is required")
+			}
+			for i := 0; i < list.Len(); i++ {
+				v.value(fmt.Sprintf("%s[%d]", name, i), f, list.Get(i))
+			}
+		case f.fd.IsMap():
+			entries := m.Get(f.fd).Map()
+			if required && entries.Len() == 0 {
+				v.add(name, "is required")
+			}
+			if f.fd.MapValue().Message() != nil {
+				entries.Range(func(k protoreflect.MapKey, val protoreflect.Value) bool {
+					v.message(fmt.Sprintf("%s[%v].", name, k.Interface()), val.Message())
+					return true
+				})
+			}
+		default:
+			if required && !m.Has(f.fd) {
+				v.add(name, "is required")
+			}
+			// Scalars without presence are checked even when zero, so a
+			// range excluding zero also rejects a missing value.
+			if m.Has(f.fd) || !f.fd.HasPresence() {
+				v.value(name, f, m.Get(f.fd))
+			}
+		}
+	}
+}
+
+// value checks one value of f: a singular field or an element of a list.
+func (v *violations) value(name string, f field, val protoreflect.Value) {
+	if f.fd.Message() != nil {
+		v.message(name+".", val.Message())
+		return
+	}
+	if f.rules == nil {
+		return
+	}
+
+	r := f.rules
+	switch f.fd.Kind() {
+	case protoreflect.StringKind:
+		s := val.String()
+		n := utf8.RuneCountInString(s)
+		if r.GetMinLen() > 0 && n < int(r.GetMinLen()) {
+			v.add(name, "must be at least %d characters", r.GetMinLen())
+		}
+		if r.GetMaxLen() > 0 && n > int(r.GetMaxLen()) {
+			v.add(name, "must be at most %d characters", r.GetMaxLen())
+		}
+		if f.pattern != nil && !f.pattern.MatchString(s) {
+			v.add(name, "must match %s", f.pattern)
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n := val.Int()
+		if r.Gte != nil && n < r.GetGte() {
+			v.add(name, "must be at least %d", r.GetGte())
+		}
+		if r.Lte != nil && n > r.GetLte() {
+			v.add(name, "must be at most %d", r.GetLte())
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n := val.Uint()
+		if r.Gte != nil && r.GetGte() > 0 && n < uint64(r.GetGte()) {
+			v.add(name, "must be at least %d", r.GetGte())
+		}
+		if r.Lte != nil && (r.GetLte() < 0 || n > uint64(r.GetLte())) {
+			v.add(name, "must be at most %d", r.GetLte())
+		}
+	}
+}
+
+// UnaryInterceptor refuses invalid requests.
+func UnaryInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if m, ok := req.(proto.Message); ok {
+		if err := Check(m); err != nil {
+			return nil, err
+		}
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor checks every message the client streams. An invalid one
+// is returned to the handler as a receive error, which ends the call with it.
+func StreamInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &checkedStream{ss})
+}
+
+type checkedStream struct {
+	grpc.ServerStream
+}
+
+func (s *checkedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		return Check(msg)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package validate_test
+
+import (
+	"context"
+	"github.com/ferza17/grpc-course/calculator/calculatorpb"
+	calculatorv1 "github.com/ferza17/grpc-course/calculator/v1"
+	"github.com/ferza17/grpc-course/greet/greetpb"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fields returns the names of the fields v complains about.
+func fields(v []*errdetails.BadRequest_FieldViolation) []string {
+	var names []string
+	for _, fv := range v {
+		names = append(names, fv.GetField())
+	}
+	return names
+}
+
+func TestViolations(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  proto.Message
+		want []string
+	}{
+		{
+			name: "valid greeting",
+			msg:  &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Ann", LastName: "Lee"}},
+		},
+		{
+			name: "no last name",
+			msg:  &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Ann"}},
+		},
+		{
+			name: "no greeting",
+			msg:  &greetv1.GreetRequest{},
+			want: []string{"greeting"},
+		},
+		{
+			name: "empty first name",
+			msg:  &greetv1.GreetRequest{Greeting: &greetv1.Greeting{LastName: "Lee"}},
+			want: []string{"greeting.first_name"},
+		},
+		{
+			name: "names too long",
+			msg: &greetv1.LongGreetRequest{Greeting: &greetv1.Greeting{
+				FirstName: strings.Repeat("a", 65),
+				LastName:  strings.Repeat("é", 65),
+			}},
+			want: []string{"greeting.first_name", "greeting.last_name"},
+		},
+		{
+			name: "longest names",
+			msg: &greetv1.LongGreetRequest{Greeting: &greetv1.Greeting{
+				FirstName: strings.Repeat("é", 64),
+				LastName:  strings.Repeat("a", 64),
+			}},
+		},
+		{
+			name: "control character",
+			msg:  &greetv1.GreetEveryoneRequest{Greeting: &greetv1.Greeting{FirstName: "Ann\x1b[2J"}},
+			want: []string{"greeting.first_name"},
+		},
+		{
+			name: "legacy greeting",
+			msg:  &greetpb.GreatRequest{Greeting: &greetpb.Greeting{}},
+			want: []string{"greeting.first_name"},
+		},
+		{
+			name: "sum",
+			msg:  &calculatorv1.SumRequest{Operands: &calculatorv1.Operands{}},
+		},
+		{
+			name: "no operands",
+			msg:  &calculatorv1.SumRequest{},
+			want: []string{"operands"},
+		},
+		{
+			name: "legacy no operands",
+			msg:  &calculatorpb.SumRequest{},
+			want: []string{"sum"},
+		},
+		{
+			name: "decompose zero",
+			msg:  &calculatorv1.DecomposeRequest{},
+		},
+		{
+			name: "decompose negative",
+			msg:  &calculatorv1.DecomposeRequest{Number: -12},
+			want: []string{"number"},
+		},
+		{
+			name: "legacy decompose negative",
+			msg:  &calculatorpb.SumManyTimesRequest{Total: -12},
+			want: []string{"total"},
+		},
+		{
+			name: "no rules",
+			msg:  &calculatorv1.AverageRequest{Number: -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(validate.Violations(tt.msg)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := validate.Check(&greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Ann"}}); err != nil {
+		t.Fatalf("Check(valid) = %v", err)
+	}
+
+	err := validate.Check(&greetv1.GreetRequest{Greeting: &greetv1.Greeting{LastName: strings.Repeat("a", 65)}})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Check = %v, want InvalidArgument", err)
+	}
+	want := "invalid GreetRequest: greeting.first_name is required; greeting.last_name must be at most 64 characters"
+	if st.Message() != want {
+		t.Errorf("message = %q, want %q", st.Message(), want)
+	}
+	var got []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			got = fields(br.GetFieldViolations())
+		}
+	}
+	if want := []string{"greeting.first_name", "greeting.last_name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BadRequest fields = %v, want %v", got, want)
+	}
+}
+
+func validating() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(validate.UnaryInterceptor),
+		grpc.StreamInterceptor(validate.StreamInterceptor),
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	c := greetv1.NewGreetServiceClient(harness.Greet(t, validating()...).Conn)
+
+	if _, err := c.Greet(context.Background(), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Ann"}}); err != nil {
+		t.Fatalf("Greet(valid): %v", err)
+	}
+	_, err := c.Greet(context.Background(), &greetv1.GreetRequest{Greeting: &greetv1.Greeting{}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Greet(empty first name) = %v, want InvalidArgument", err)
+	}
+}
+
+func TestUnaryInterceptorLegacy(t *testing.T) {
+	c := calculatorpb.NewSumServiceClient(harness.Calculator(t, validating()...).Conn)
+
+	_, err := c.SumData(context.Background(), &calculatorpb.SumRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SumData(no sum) = %v, want InvalidArgument", err)
+	}
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	h := harness.Calculator(t, validating()...)
+	c := calculatorv1.NewCalculatorServiceClient(h.Conn)
+
+	stream, err := c.Decompose(context.Background(), &calculatorv1.DecomposeRequest{Number: -12})
+	if err != nil {
+		t.Fatalf("Decompose: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Decompose(-12) Recv = %v, want InvalidArgument", err)
+	}
+
+	g := greetv1.NewGreetServiceClient(harness.Greet(t, validating()...).Conn)
+	long, err := g.LongGreet(context.Background())
+	if err != nil {
+		t.Fatalf("LongGreet: %v", err)
+	}
+	for _, first := range []string{"Ann", ""} {
+		if err := long.Send(&greetv1.LongGreetRequest{Greeting: &greetv1.Greeting{FirstName: first}}); err != nil {
+			break // the server has already ended the call
+		}
+	}
+	if _, err := long.CloseAndRecv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("LongGreet(empty first name) = %v, want InvalidArgument", err)
+	}
+}
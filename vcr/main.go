@@ -0,0 +1,134 @@
+// Command vcr sits between a client and a server, such as greet_client and
+// greet_server, and records their calls to a cassette, or plays a cassette
+// back in place of the server. See package cassette.
+//
+//	vcr record -listen :50061 -backend localhost:50051 greet.cassette
+//	vcr play -listen :50061 -speed 0 greet.cassette
+//
+// Any method of any service is forwarded. vcr stops on interrupt, after the
+// calls in flight have ended.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ferza17/grpc-course/cassette"
+	"github.com/ferza17/grpc-course/config"
+	"github.com/ferza17/grpc-course/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"log"
+	"os"
+	"os/signal"
+)
+
+// usageError is returned for bad command lines, it exits with 2.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		var uerr usageError
+		if errors.As(err, &uerr) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintln(out, "  vcr record [-listen addr] [-backend addr] cassette")
+	fmt.Fprintln(out, "  vcr play [-listen addr] [-speed n] cassette")
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return usageError{"expected record or play"}
+	}
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	listen := fs.String("listen", "localhost:50061", "address to serve on, host:port or unix:///path")
+	switch args[0] {
+	case "record":
+		backend := fs.String("backend", "localhost:50051", "server to forward calls to")
+		path, err := parse(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		cc, err := grpc.NewClient(*backend, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return err
+		}
+		defer cc.Close()
+		rec, err := cassette.Record(cc, path)
+		if err != nil {
+			return err
+		}
+		defer rec.Close()
+		log.Printf("Recording calls to %s in %s", *backend, path)
+		return serve(*listen, rec.Handler)
+
+	case "play":
+		speed := fs.Float64("speed", 1, "how many times faster than recorded to respond, 0 responds at once")
+		path, err := parse(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		c, err := cassette.Load(path)
+		if err != nil {
+			return err
+		}
+		p := cassette.NewPlayer(c, *speed)
+		log.Printf("Playing %d calls from %s", len(c.Interactions), path)
+		if err := serve(*listen, p.Handler); err != nil {
+			return err
+		}
+		if n := len(p.Unplayed()); n > 0 {
+			log.Printf("%d recorded calls were not played", n)
+		}
+		return nil
+
+	default:
+		return usageError{fmt.Sprintf("unknown command %q", args[0])}
+	}
+}
+
+// parse parses the flags of a command and returns its cassette argument.
+func parse(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", usageError{err.Error()}
+	}
+	if fs.NArg() != 1 {
+		return "", usageError{"expected one cassette file"}
+	}
+	return fs.Arg(0), nil
+}
+
+// serve passes the calls on addr to handler until interrupted.
+func serve(addr string, handler grpc.StreamHandler) error {
+	lis, err := transport.Listen(addr, config.Transport{})
+	if err != nil {
+		return err
+	}
+	s := grpc.NewServer(cassette.ServerOptions(handler)...)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		s.GracefulStop()
+	}()
+	log.Printf("Listening on %s", lis.Addr())
+	return s.Serve(lis)
+}
@@ -0,0 +1,242 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/deadline"
+	"github.com/ferza17/grpc-course/rawcodec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	contentTypeGRPCWeb     = "application/grpc-web"
+	contentTypeGRPCWebText = "application/grpc-web-text"
+
+	dataFrame    byte = 0x00
+	trailerFrame byte = 0x80
+)
+
+// headers that describe the HTTP exchange itself and are never forwarded as
+// gRPC metadata.
+var skipHeaders = map[string]bool{
+	"accept": true, "accept-encoding": true, "accept-language": true, "connection": true,
+	"content-length": true, "content-type": true, "cookie": true, "grpc-timeout": true, "host": true,
+	"origin": true, "referer": true, "te": true, "user-agent": true, "x-grpc-web": true, "x-user-agent": true,
+}
+
+type grpcWeb struct {
+	cc   grpc.ClientConnInterface
+	opts Options
+	next http.Handler
+}
+
+// GRPCWeb returns a handler that translates gRPC-Web requests (both the
+// binary and the base64 text encoding) into calls on cc. Requests that are not
+// gRPC-Web are passed to next. Browsers cannot stream request bodies, so every
+// request message has to be in the body; bidi methods need WebSocket instead.
+func GRPCWeb(cc grpc.ClientConnInterface, opts Options, next http.Handler) http.Handler {
+	return &grpcWeb{cc: cc, opts: opts, next: next}
+}
+
+func isGRPCWeb(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), contentTypeGRPCWeb)
+}
+
+func (g *grpcWeb) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions && strings.Contains(strings.ToLower(r.Header.Get("Access-Control-Request-Headers")), "x-grpc-web") {
+		g.preflight(w, r)
+		return
+	}
+	if !isGRPCWeb(r) {
+		g.next.ServeHTTP(w, r)
+		return
+	}
+	if !g.opts.originAllowed(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Expose-Headers", "grpc-status, grpc-message")
+		w.Header().Add("Vary", "Origin")
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	text := strings.HasPrefix(contentType, contentTypeGRPCWebText)
+
+	// Browsers send the request message in a single frame.
+	maxSize := g.opts.maxMessageSize()
+	bodySize := maxSize + 5
+	if text {
+		bodySize = int64(base64.StdEncoding.EncodedLen(int(bodySize)))
+	}
+	var body io.Reader = http.MaxBytesReader(w, r.Body, bodySize)
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	requests, err := readFrames(body, maxSize)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || errors.Is(err, errFrameTooLarge) {
+		http.Error(w, fmt.Sprintf("request larger than %d bytes", maxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := metadata.NewOutgoingContext(r.Context(), incomingMetadata(r))
+	if timeout, ok := deadline.ParseTimeout(r.Header.Get("Grpc-Timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	fw := &frameWriter{w: w, text: text}
+	fw.flusher, _ = w.(http.Flusher)
+
+	desc := &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}
+	stream, err := g.cc.NewStream(ctx, desc, r.URL.Path, grpc.ForceCodec(rawcodec.Codec{}))
+	if err != nil {
+		fw.trailer(err, nil)
+		return
+	}
+	for _, req := range requests {
+		if err := stream.SendMsg(&req); err != nil {
+			break
+		}
+	}
+	stream.CloseSend()
+
+	headerSent := false
+	for {
+		var res []byte
+		err := stream.RecvMsg(&res)
+		if !headerSent {
+			header, _ := stream.Header()
+			for key, values := range header {
+				if key == "content-type" {
+					continue
+				}
+				for _, v := range values {
+					w.Header().Add(key, v)
+				}
+			}
+			headerSent = true
+		}
+		if err == io.EOF {
+			fw.trailer(nil, stream.Trailer())
+			return
+		}
+		if err != nil {
+			fw.trailer(err, stream.Trailer())
+			return
+		}
+		fw.frame(dataFrame, res)
+	}
+}
+
+func (g *grpcWeb) preflight(w http.ResponseWriter, r *http.Request) {
+	if !g.opts.originAllowed(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+	w.Header().Set("Access-Control-Allow-Methods", http.MethodPost)
+	w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+	w.Header().Set("Access-Control-Max-Age", "600")
+	w.Header().Add("Vary", "Origin")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+var errFrameTooLarge = errors.New("frame too large")
+
+// readFrames splits a gRPC-Web request body into its message payloads, none
+// of which may be larger than maxSize.
+func readFrames(r io.Reader, maxSize int64) ([][]byte, error) {
+	var messages [][]byte
+	var prefix [5]byte
+	for {
+		if _, err := io.ReadFull(r, prefix[:]); err == io.EOF {
+			return messages, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("read frame header: %w", err)
+		}
+
+		size := binary.BigEndian.Uint32(prefix[1:])
+		if int64(size) > maxSize {
+			return nil, fmt.Errorf("read frame: %d bytes: %w", size, errFrameTooLarge)
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return nil, fmt.Errorf("read frame: %w", err)
+		}
+		if prefix[0]&trailerFrame == 0 {
+			messages = append(messages, payload)
+		}
+	}
+}
+
+type frameWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	text    bool
+}
+
+func (fw *frameWriter) frame(flag byte, payload []byte) {
+	frame := make([]byte, 5+len(payload))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	copy(frame[5:], payload)
+
+	if fw.text {
+		// Each frame is padded on its own, which the text encoding allows.
+		fw.w.Write([]byte(base64.StdEncoding.EncodeToString(frame)))
+	} else {
+		fw.w.Write(frame)
+	}
+	if fw.flusher != nil {
+		fw.flusher.Flush()
+	}
+}
+
+// trailer ends the response with the call status, encoded as an HTTP/1 header
+// block inside a trailer frame as gRPC-Web requires.
+func (fw *frameWriter) trailer(err error, md metadata.MD) {
+	st := status.Convert(err)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "grpc-status: %d\r\n", st.Code())
+	fmt.Fprintf(&buf, "grpc-message: %s\r\n", url.PathEscape(st.Message()))
+	for key, values := range md {
+		for _, v := range values {
+			fmt.Fprintf(&buf, "%s: %s\r\n", key, v)
+		}
+	}
+	fw.frame(trailerFrame, buf.Bytes())
+}
+
+func incomingMetadata(r *http.Request) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		key = strings.ToLower(key)
+		if skipHeaders[key] || strings.HasPrefix(key, "access-control-") || strings.HasPrefix(key, "sec-") {
+			continue
+		}
+		md.Append(key, values...)
+	}
+	auth.SetForwardedFor(md, r)
+	return md
+}
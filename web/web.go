@@ -0,0 +1,44 @@
+// Package web lets browsers reach the gRPC services. GRPCWeb speaks the
+// gRPC-Web protocol for unary and server-streaming calls, WebSocket bridges
+// every method, including bidi streams, over a WebSocket connection.
+package web
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// DefaultMaxMessageSize is the grpc-go default of the largest message a
+// server receives.
+const DefaultMaxMessageSize = 4 << 20
+
+// Options are the settings shared by the browser-facing handlers.
+type Options struct {
+	// AllowedOrigins lists the origins allowed to call cross-site, "*" allows
+	// any. Same-origin requests are always allowed.
+	AllowedOrigins []string
+	// MaxMessageSize bounds request messages in bytes, zero means
+	// DefaultMaxMessageSize. Servers set it to their largest received message.
+	MaxMessageSize int64
+}
+
+func (o Options) maxMessageSize() int64 {
+	if o.MaxMessageSize <= 0 {
+		return DefaultMaxMessageSize
+	}
+	return o.MaxMessageSize
+}
+
+func (o Options) originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
@@ -0,0 +1,80 @@
+package web_test
+
+import (
+	"bytes"
+	"errors"
+	greetv1 "github.com/ferza17/grpc-course/greet/v1"
+	"github.com/ferza17/grpc-course/harness"
+	"github.com/ferza17/grpc-course/web"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const maxSize = 64
+
+var greetService = greetv1.File_greet_v1_greet_proto.Services().ByName("GreetService")
+
+// frame returns a gRPC-Web data frame announcing size bytes of payload.
+func frame(size uint32, payload []byte) []byte {
+	return append([]byte{0, byte(size >> 24), byte(size >> 16), byte(size >> 8), byte(size)}, payload...)
+}
+
+func TestGRPCWebMaxMessageSize(t *testing.T) {
+	h := web.GRPCWeb(harness.Greet(t).Conn, web.Options{MaxMessageSize: maxSize}, http.NotFoundHandler())
+	greeting, _ := proto.Marshal(&greetv1.GreetRequest{Greeting: &greetv1.Greeting{FirstName: "Ann"}})
+
+	for _, tt := range []struct {
+		name string
+		body []byte
+		want int
+	}{
+		{"small", frame(uint32(len(greeting)), greeting), http.StatusOK},
+		// Only the prefix is sent: the length alone must be refused.
+		{"huge length", frame(1<<32-1, nil), http.StatusRequestEntityTooLarge},
+		{"long body", frame(maxSize, bytes.Repeat([]byte{'a'}, 2*maxSize)), http.StatusRequestEntityTooLarge},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/greet.v1.GreetService/Greet", bytes.NewReader(tt.body))
+		r.Header.Set("Content-Type", "application/grpc-web+proto")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d %s, want %d", tt.name, w.Code, w.Body, tt.want)
+		}
+	}
+}
+
+func TestWebSocketMaxMessageSize(t *testing.T) {
+	srv := httptest.NewServer(web.WebSocket(harness.Greet(t).Conn, web.Options{MaxMessageSize: maxSize}, greetService))
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + web.WebSocketPrefix + "/greet.v1.GreetService/Greet?encoding=proto"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	msg := append([]byte{web.KindMessage}, bytes.Repeat([]byte{'a'}, 2*maxSize)...)
+	if err := conn.WriteMessage(websocket.BinaryMessage, msg); err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			if closeErr.Code != websocket.CloseMessageTooBig {
+				t.Errorf("close code = %d, want %d", closeErr.Code, websocket.CloseMessageTooBig)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("ReadMessage: %v", err)
+		}
+	}
+}
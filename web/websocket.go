@@ -0,0 +1,238 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ferza17/grpc-course/auth"
+	"github.com/ferza17/grpc-course/deadline"
+	"github.com/ferza17/grpc-course/rawcodec"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocketPrefix is the path under which WebSocket serves methods, as
+// /ws/<package.Service>/<Method>.
+const WebSocketPrefix = "/ws"
+
+// Binary frames start with one of these kinds, the rest of the frame is the
+// protobuf payload.
+const (
+	// KindMessage carries a request or response message.
+	KindMessage byte = 0
+	// KindCloseSend is sent by the browser to half-close its side, the
+	// equivalent of ClientStream.CloseSend.
+	KindCloseSend byte = 1
+	// KindStatus is the last frame from the server and carries a google.rpc.Status.
+	KindStatus byte = 2
+)
+
+// envelope is the JSON framing used on text frames. The browser sends either
+// a message or closeSend, the server sends messages followed by one status.
+type envelope struct {
+	Message   json.RawMessage     `json:"message,omitempty"`
+	CloseSend bool                `json:"closeSend,omitempty"`
+	Status    json.RawMessage     `json:"status,omitempty"`
+	Trailer   map[string][]string `json:"trailer,omitempty"`
+}
+
+type webSocket struct {
+	cc       grpc.ClientConnInterface
+	methods  map[string]protoreflect.MethodDescriptor
+	upgrader websocket.Upgrader
+	maxSize  int64
+}
+
+// WebSocket returns a handler that bridges a WebSocket connection to one call
+// of any method of services on cc. The query parameter encoding selects "json"
+// (text frames holding an envelope, the default) or "proto" (binary frames
+// prefixed with a Kind byte). Query parameters other than encoding are sent as
+// request metadata, since browsers cannot set headers on a WebSocket, except
+// grpc-timeout which sets the call deadline. x-forwarded-for is always the
+// address of the browser, see package auth.
+func WebSocket(cc grpc.ClientConnInterface, opts Options, services ...protoreflect.ServiceDescriptor) http.Handler {
+	h := &webSocket{cc: cc, methods: map[string]protoreflect.MethodDescriptor{}, maxSize: opts.maxMessageSize()}
+	h.upgrader.CheckOrigin = opts.originAllowed
+	for _, sd := range services {
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			h.methods["/"+string(sd.FullName())+"/"+string(md.Name())] = md
+		}
+	}
+	return h
+}
+
+func (h *webSocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, WebSocketPrefix)
+	md, ok := h.methods[method]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	binaryEncoding := r.URL.Query().Get("encoding") == "proto"
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if binaryEncoding {
+		conn.SetReadLimit(1 + h.maxSize)
+	} else {
+		// The JSON of a message is larger than its wire form, though not
+		// twice as large but for bytes fields, which base64 grows by a third.
+		conn.SetReadLimit(2 * h.maxSize)
+	}
+
+	header := metadata.MD{}
+	for key, values := range r.URL.Query() {
+		if key != "encoding" && key != "grpc-timeout" {
+			header.Append(key, values...)
+		}
+	}
+	auth.SetForwardedFor(header, r)
+	ctx := metadata.NewOutgoingContext(r.Context(), header)
+	if timeout, ok := deadline.ParseTimeout(r.URL.Query().Get("grpc-timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	desc := &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}
+	stream, err := h.cc.NewStream(ctx, desc, method, grpc.ForceCodec(rawcodec.Codec{}))
+	if err != nil {
+		writeStatus(conn, binaryEncoding, err, nil)
+		return
+	}
+
+	// gorilla/websocket allows a single concurrent writer. Messages are only
+	// written by the receive loop below, the reader goroutine sticks to
+	// WriteControl which may be called concurrently.
+	var once sync.Once
+	closeSend := func() { once.Do(func() { stream.CloseSend() }) }
+
+	go func() {
+		defer closeSend()
+		for {
+			kind, data, err := conn.ReadMessage()
+			if errors.Is(err, websocket.ErrReadLimit) {
+				// The oversized message must not be left out of the call.
+				cancel()
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			payload, half, err := decodeClientFrame(md, kind, data)
+			if err != nil {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseUnsupportedData, err.Error()), time.Time{})
+				return
+			}
+			if half {
+				return
+			}
+			if err := stream.SendMsg(&payload); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		var res []byte
+		err := stream.RecvMsg(&res)
+		if err == io.EOF {
+			writeStatus(conn, binaryEncoding, nil, stream.Trailer())
+			return
+		}
+		if err != nil {
+			writeStatus(conn, binaryEncoding, err, stream.Trailer())
+			return
+		}
+
+		if binaryEncoding {
+			err = conn.WriteMessage(websocket.BinaryMessage, append([]byte{KindMessage}, res...))
+		} else {
+			var msg []byte
+			msg, err = toJSON(md.Output(), res)
+			if err == nil {
+				err = conn.WriteJSON(envelope{Message: msg})
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// decodeClientFrame returns the protobuf payload of a browser frame, or
+// half == true for a close-send frame.
+func decodeClientFrame(md protoreflect.MethodDescriptor, kind int, data []byte) (payload []byte, half bool, err error) {
+	if kind == websocket.BinaryMessage {
+		if len(data) == 0 {
+			return nil, false, errors.New("empty frame")
+		}
+		if data[0] == KindCloseSend {
+			return nil, true, nil
+		}
+		return data[1:], false, nil
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, false, err
+	}
+	if env.CloseSend {
+		return nil, true, nil
+	}
+
+	msg := newMessage(md.Input())
+	if err := protojson.Unmarshal(env.Message, msg); err != nil {
+		return nil, false, err
+	}
+	payload, err = proto.Marshal(msg)
+	return payload, false, err
+}
+
+func writeStatus(conn *websocket.Conn, binaryEncoding bool, err error, trailer metadata.MD) {
+	st := status.Convert(err).Proto()
+	if binaryEncoding {
+		data, _ := proto.Marshal(st)
+		conn.WriteMessage(websocket.BinaryMessage, append([]byte{KindStatus}, data...))
+	} else {
+		data, _ := protojson.Marshal(st)
+		conn.WriteJSON(envelope{Status: data, Trailer: trailer})
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+func toJSON(md protoreflect.MessageDescriptor, data []byte) ([]byte, error) {
+	msg := newMessage(md)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+	return protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+}
+
+func newMessage(md protoreflect.MessageDescriptor) proto.Message {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName()); err == nil {
+		return mt.New().Interface()
+	}
+	return dynamicpb.NewMessage(md)
+}